@@ -0,0 +1,146 @@
+package crsf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+var (
+	ErrNotStarted          = errors.New("crsf is not started")
+	ErrBaudRateUnsupported = errors.New("transport can not change baud rate")
+	ErrBaudRateRejected    = errors.New("baud rate rejected by device")
+	ErrBaudRateSyncLost    = errors.New("no frames received at the new baud rate")
+)
+
+// BaudRate is the current line speed, it changes after a successful NegotiateBaudRate
+func (c *CRSF) BaudRate() int {
+	c.transportLock.Lock()
+	defer c.transportLock.Unlock()
+	return c.baudRate
+}
+
+// NegotiateBaudRate proposes a new baud rate for port of device with a PROTOCOL_SPEED_PROPOSAL command.
+// Once the device accepts, the transport is switched and frames must arrive within BaudRateSyncTimeout
+// or the previous baud rate is restored and ErrBaudRateSyncLost is returned.
+// After that the previous rate is also restored if frames stop for BaudRateSyncTimeout.
+func (c *CRSF) NegotiateBaudRate(ctx context.Context, device frames.AddressType, port uint8, baudRate int) error {
+	c.transportLock.Lock()
+	transport := c.transport
+	c.transportLock.Unlock()
+	if transport == nil || c.Status() == StatusClosed {
+		return ErrNotStarted
+	}
+	setter, ok := transport.(BaudRateSetter)
+	if !ok {
+		return ErrBaudRateUnsupported
+	}
+
+	proposal := frames.NewSpeedProposalCommand(frames.SpeedProposalData{
+		Port:     port,
+		BaudRate: uint32(baudRate),
+	})
+	reply, err := c.RequestCommand(ctx, device, proposal, frames.CommandRealmGeneral, frames.CommandGeneralSpeedResponse)
+	if err != nil {
+		return fmt.Errorf("failed proposing baud rate %d: %w", baudRate, err)
+	}
+	response, err := frames.UnmarshalSpeedResponse(reply)
+	if err != nil {
+		return err
+	}
+	if !response.Accepted {
+		return fmt.Errorf("%w: %d", ErrBaudRateRejected, baudRate)
+	}
+
+	previous := c.BaudRate()
+	err = c.setBaudRate(setter, baudRate, previous)
+	if err != nil {
+		return err
+	}
+	slog.Info("baud rate changed", "path", c.path, "baud_rate", baudRate, "previous", previous)
+
+	if c.opts.BaudRateSyncTimeout <= 0 {
+		return nil
+	}
+
+	//frames decoded after the switch prove the device is talking at the new rate
+	changed := time.Now()
+	deadline := time.NewTimer(c.opts.BaudRateSyncTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if c.stats.lastFrame().After(changed) {
+				return nil
+			}
+		case <-deadline.C:
+			err = c.setBaudRate(setter, previous, 0)
+			if err != nil {
+				return fmt.Errorf("%w: %d, failed restoring %d: %w", ErrBaudRateSyncLost, baudRate, previous, err)
+			}
+			return fmt.Errorf("%w: %d, restored %d", ErrBaudRateSyncLost, baudRate, previous)
+		}
+	}
+}
+
+func (c *CRSF) setBaudRate(setter BaudRateSetter, baudRate, fallback int) error {
+	c.transportLock.Lock()
+	defer c.transportLock.Unlock()
+
+	err := setter.SetBaudRate(baudRate)
+	if err != nil {
+		return fmt.Errorf("failed setting baud rate %d: %w", baudRate, err)
+	}
+	c.baudRate = baudRate
+	c.fallbackBaudRate = fallback
+	c.baudRateChanged = time.Now()
+	return nil
+}
+
+// startBaudRateMonitor restores the fallback baud rate when frames stop after a negotiated change
+func (c *CRSF) startBaudRateMonitor() error {
+	c.transportLock.Lock()
+	setter := c.transport.(BaudRateSetter)
+	c.transportLock.Unlock()
+	ticker := time.NewTicker(max(c.opts.BaudRateSyncTimeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-ticker.C:
+			c.transportLock.Lock()
+			fallback := c.fallbackBaudRate
+			changed := c.baudRateChanged
+			current := c.baudRate
+			c.transportLock.Unlock()
+
+			if fallback == 0 {
+				continue
+			}
+
+			lastFrame := c.stats.lastFrame()
+			if lastFrame.Before(changed) {
+				lastFrame = changed
+			}
+			if time.Since(lastFrame) < c.opts.BaudRateSyncTimeout {
+				continue
+			}
+
+			slog.Warn("lost sync after baud rate change, falling back", "path", c.path, "baud_rate", current, "fallback", fallback)
+			err := c.setBaudRate(setter, fallback, 0)
+			if err != nil {
+				slog.Error("failed restoring baud rate", "path", c.path, "fallback", fallback, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package crsf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+/*
+Capture files start with CaptureMagic followed by records of
+[offset int64 nanoseconds since the capture started][direction byte][length uint16][data]
+with all integers big-endian.
+*/
+const (
+	CaptureMagic        = "CRSFCAP1"
+	captureHeaderLength = 8 + 1 + 2
+)
+
+var ErrInvalidCapture = errors.New("not a crsf capture")
+
+type CaptureDirection byte
+
+const (
+	CaptureRead  CaptureDirection = 'R' // bytes read from the transport
+	CaptureWrite CaptureDirection = 'W' // frames written to the transport
+)
+
+type CaptureRecord struct {
+	Offset    time.Duration // time since the capture started
+	Direction CaptureDirection
+	Data      []byte
+}
+
+// Recorder writes timestamped raw bytes to a capture, pass it to WithRecorder
+type Recorder struct {
+	lock  sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewRecorder writes the capture header to w
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	_, err := io.WriteString(w, CaptureMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed writing capture header: %w", err)
+	}
+	return &Recorder{
+		w:     w,
+		start: time.Now(),
+	}, nil
+}
+
+// Record writes a copy of data to the capture
+func (r *Recorder) Record(direction CaptureDirection, data []byte) error {
+	if len(data) > 0xFFFF {
+		return fmt.Errorf("capture record too long: %d", len(data))
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	record := make([]byte, captureHeaderLength, captureHeaderLength+len(data))
+	binary.BigEndian.PutUint64(record[0:8], uint64(time.Since(r.start)))
+	record[8] = byte(direction)
+	binary.BigEndian.PutUint16(record[9:11], uint16(len(data)))
+	record = append(record, data...)
+
+	_, err := r.w.Write(record)
+	if err != nil {
+		return fmt.Errorf("failed writing capture record: %w", err)
+	}
+	return nil
+}
+
+// CaptureReader reads records back from a capture
+type CaptureReader struct {
+	r *bufio.Reader
+}
+
+// NewCaptureReader checks the capture header of r
+func NewCaptureReader(r io.Reader) (*CaptureReader, error) {
+	reader := bufio.NewReader(r)
+	magic := make([]byte, len(CaptureMagic))
+	_, err := io.ReadFull(reader, magic)
+	if err != nil || string(magic) != CaptureMagic {
+		return nil, ErrInvalidCapture
+	}
+	return &CaptureReader{r: reader}, nil
+}
+
+// Next returns the next record or io.EOF at the end of the capture
+func (r *CaptureReader) Next() (CaptureRecord, error) {
+	header := make([]byte, captureHeaderLength)
+	_, err := io.ReadFull(r.r, header)
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return CaptureRecord{}, fmt.Errorf("truncated capture record: %w", err)
+		}
+		return CaptureRecord{}, err
+	}
+
+	record := CaptureRecord{
+		Offset:    time.Duration(binary.BigEndian.Uint64(header[0:8])),
+		Direction: CaptureDirection(header[8]),
+		Data:      make([]byte, binary.BigEndian.Uint16(header[9:11])),
+	}
+	_, err = io.ReadFull(r.r, record.Data)
+	if err != nil {
+		return CaptureRecord{}, fmt.Errorf("truncated capture record: %w", err)
+	}
+	return record, nil
+}
+
+// ReplayTransport is a Transport that plays back the read side of a capture, writes are discarded.
+// Use it with WithTransport to feed a capture through the parser.
+type ReplayTransport struct {
+	capture *CaptureReader
+	speed   float64
+	start   time.Time
+	pending []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewReplayTransport replays the capture in r. A speed of 1 keeps the original timing, 2 plays twice as fast
+// and 0 or less plays as fast as the reader consumes it.
+func NewReplayTransport(r io.Reader, speed float64) (*ReplayTransport, error) {
+	capture, err := NewCaptureReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayTransport{
+		capture: capture,
+		speed:   speed,
+		closed:  make(chan struct{}),
+	}, nil
+}
+
+// Read returns io.EOF once the capture has been played
+func (t *ReplayTransport) Read(p []byte) (int, error) {
+	select {
+	case <-t.closed:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	for len(t.pending) == 0 {
+		record, err := t.capture.Next()
+		if err != nil {
+			return 0, err
+		}
+		if record.Direction != CaptureRead {
+			continue
+		}
+
+		err = t.wait(record.Offset)
+		if err != nil {
+			return 0, err
+		}
+		t.pending = record.Data
+	}
+
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *ReplayTransport) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (t *ReplayTransport) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.closed)
+	})
+	return nil
+}
+
+// wait sleeps until the scaled offset of a record since the first read
+func (t *ReplayTransport) wait(offset time.Duration) error {
+	if t.speed <= 0 {
+		return nil
+	}
+	if t.start.IsZero() {
+		t.start = time.Now().Add(-time.Duration(float64(offset) / t.speed))
+	}
+
+	delay := time.Until(t.start.Add(time.Duration(float64(offset) / t.speed)))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-t.closed:
+		return io.ErrClosedPipe
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,389 @@
+// Command crsf monitors, dumps, sends, records and replays CRSF from a serial port.
+//
+//	crsf monitor -port /dev/ttyUSB0
+//	crsf dump -port /dev/ttyUSB0
+//	crsf sniff -port /dev/ttyUSB0 -invalid
+//	crsf send -port /dev/ttyUSB0 -channels 1500,1500,988,1500
+//	crsf record -port /dev/ttyUSB0 -out flight.crsf
+//	crsf replay -in flight.crsf -speed 2
+//	crsf ping -port /dev/ttyUSB0
+//	crsf export -port /dev/ttyUSB0 -listen :9100
+//	crsf mavlink -port /dev/ttyUSB0 -remote 127.0.0.1:14550
+//	crsf msp -port /dev/ttyUSB0 -cmd 101
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Speshl/go-crsf"
+	"github.com/Speshl/go-crsf/exporter"
+	"github.com/Speshl/go-crsf/frames"
+	"github.com/Speshl/go-crsf/mavlink"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"monitor", "live view of the decoded data", runMonitor},
+	{"dump", "print every decoded frame as hex and text", runDump},
+	{"sniff", "print every raw frame, including unknown types", runSniff},
+	{"send", "send channel values or telemetry frames", runSend},
+	{"record", "record the raw byte stream to a capture file", runRecord},
+	{"replay", "decode a capture file", runReplay},
+	{"ping", "discover devices on the bus", runPing},
+	{"export", "serve prometheus metrics on /metrics", runExport},
+	{"mavlink", "bridge telemetry to a MAVLink ground station over UDP", runMavlink},
+	{"msp", "send an MSP command to the flight controller over CRSF", runMsp},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+		err := cmd.run(ctx, os.Args[2:])
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "crsf %s: %s\n", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: crsf <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "run crsf <command> -h for the flags of a command")
+}
+
+// portFlags are shared by every command that opens a serial port
+type portFlags struct {
+	path      string
+	baud      int
+	timeout   int
+	reconnect bool
+}
+
+func addPortFlags(fs *flag.FlagSet) *portFlags {
+	p := &portFlags{}
+	fs.StringVar(&p.path, "port", "/dev/ttyUSB0", "serial port")
+	fs.IntVar(&p.baud, "baud", 420000, "baud rate")
+	fs.IntVar(&p.timeout, "timeout", 1000, "read timeout in milliseconds")
+	fs.BoolVar(&p.reconnect, "reconnect", false, "reopen the port when it fails or is unplugged")
+	return p
+}
+
+func (p *portFlags) options() []crsf.Option {
+	opts := []crsf.Option{
+		crsf.WithBaudRate(p.baud),
+		crsf.WithTimeout(p.timeout),
+	}
+	if p.reconnect {
+		opts = append(opts, crsf.WithReconnect(250*time.Millisecond, 5*time.Second))
+	}
+	return opts
+}
+
+// start runs c in the background, the returned channel receives the result once it stops
+func start(ctx context.Context, c *crsf.CRSF) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+	return done
+}
+
+// wait blocks until ctx is done or c stops
+func wait(ctx context.Context, done <-chan error) error {
+	select {
+	case <-ctx.Done():
+		return <-done
+	case err := <-done:
+		return err
+	}
+}
+
+func runMonitor(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	interval := fs.Duration("interval", 250*time.Millisecond, "refresh interval")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(true))...)
+	done := start(ctx, c)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return <-done
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			fmt.Print("\033[H\033[2J") //clear the terminal
+			fmt.Println(c.String())
+			fmt.Println()
+			printAges(c)
+		}
+	}
+}
+
+func runDump(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	asJSON := fs.Bool("json", false, "print one JSON object per line")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(true))...)
+	return dump(ctx, c, *asJSON)
+}
+
+func runSniff(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("sniff", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	invalid := fs.Bool("invalid", false, "also print frames that failed their crc")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(true))...)
+	c.HandleRawFrames(*invalid, printRawFrame)
+	return wait(ctx, start(ctx, c))
+}
+
+func runRecord(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	out := fs.String("out", "capture.crsf", "capture file to write")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	recorder, err := crsf.NewRecorder(file)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(true), crsf.WithRecorder(recorder))...)
+	fmt.Fprintf(os.Stderr, "recording %s to %s, interrupt to stop\n", port.path, *out)
+	return wait(ctx, start(ctx, c))
+}
+
+func runReplay(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	in := fs.String("in", "capture.crsf", "capture file to replay")
+	speed := fs.Float64("speed", 1, "playback speed, 0 plays as fast as possible")
+	asJSON := fs.Bool("json", false, "print one JSON object per line")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	transport, err := crsf.NewReplayTransport(file, *speed)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(*in, crsf.WithTransport(transport), crsf.WithReadOnly(true))
+	err = dump(ctx, c, *asJSON)
+	if !*asJSON {
+		printStats(c.Stats())
+	}
+	return err
+}
+
+func runPing(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("ping", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	waitFor := fs.Duration("wait", time.Second, "how long to wait for answers")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithWriteChannels(false))...)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := start(runCtx, c)
+
+	discoverCtx, cancel := context.WithTimeout(ctx, *waitFor)
+	defer cancel()
+	devices, err := c.Discover(discoverCtx)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		fmt.Printf("%-16s %s\n", device.Address.String(), device.Info.String())
+	}
+	if len(devices) == 0 {
+		fmt.Println("no devices answered")
+	}
+
+	stop()
+	return <-done
+}
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	listen := fs.String("listen", ":9100", "address to serve /metrics on")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(true))...)
+	exp := exporter.New(c)
+	go func() {
+		_ = exp.Run(ctx)
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "serving metrics on %s/metrics\n", *listen)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	done := start(ctx, c)
+	select {
+	case err = <-serverErr:
+		return err
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return <-done
+	}
+}
+
+func runMavlink(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("mavlink", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	local := fs.String("local", ":14551", "UDP address to listen on")
+	remote := fs.String("remote", "127.0.0.1:14550", "ground station UDP address")
+	systemID := fs.Uint("sysid", 1, "MAVLink system id")
+	overrides := fs.Bool("overrides", false, "send RC_CHANNELS_OVERRIDE from the ground station as CRSF channels")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithReadOnly(!*overrides))...)
+	bridge := mavlink.NewBridge(c,
+		mavlink.WithLocalAddress(*local),
+		mavlink.WithRemoteAddress(*remote),
+		mavlink.WithSystemID(uint8(*systemID), 1),
+		mavlink.WithChannelOverrides(*overrides),
+	)
+
+	bridgeErr := make(chan error, 1)
+	go func() {
+		bridgeErr <- bridge.Run(ctx)
+	}()
+
+	done := start(ctx, c)
+	select {
+	case err = <-bridgeErr:
+		return err
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return <-done
+	}
+}
+
+func runMsp(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("msp", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	command := fs.Uint("cmd", 101, "MSP command, 101 is MSP_STATUS")
+	data := fs.String("data", "", "request payload as hex")
+	dest := fs.Uint("dest", uint(frames.AddressTypeFlightController), "destination address")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *command > 255 {
+		return fmt.Errorf("only MSPv1 commands (0-255) are supported")
+	}
+
+	payload, err := hex.DecodeString(*data)
+	if err != nil {
+		return fmt.Errorf("invalid data: %w", err)
+	}
+
+	c := crsf.NewCRSF(port.path, append(port.options(), crsf.WithWriteChannels(false))...)
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	done := start(runCtx, c)
+
+	response, err := crsf.NewMspClient(c, frames.AddressType(*dest)).Request(ctx, uint8(*command), payload)
+	if response != nil {
+		fmt.Println(hex.EncodeToString(response))
+	}
+	if err != nil {
+		return err
+	}
+
+	stop()
+	return <-done
+}
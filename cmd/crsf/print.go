@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Speshl/go-crsf"
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// dump prints every decoded frame until ctx is done or c stops
+func dump(ctx context.Context, c *crsf.CRSF, asJSON bool) error {
+	sub := c.Subscribe(1024)
+	defer sub.Unsubscribe()
+
+	printEvent := printText
+	if asJSON {
+		ndjson := crsf.NewNDJSONWriter(os.Stdout)
+		printEvent = func(event crsf.Event) {
+			err := ndjson.Write(event)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	}
+
+	done := start(ctx, c)
+	for {
+		select {
+		case <-ctx.Done():
+			return <-done
+		case err := <-done:
+			for len(sub.C) > 0 { //print what was decoded before stopping
+				printEvent(<-sub.C)
+			}
+			return err
+		case event := <-sub.C:
+			printEvent(event)
+		}
+	}
+}
+
+func printText(event crsf.Event) {
+	name := event.Type.String()
+	if event.Kind != crsf.EventKindFrame {
+		name = event.Kind.String()
+	}
+	fmt.Printf("%s %-22s %-40s %s\n",
+		event.Time.Format("15:04:05.000"),
+		name,
+		hex.EncodeToString(event.Raw),
+		describe(event.Data),
+	)
+}
+
+func printRawFrame(frame frames.RawFrame, crcValid bool) {
+	status := "ok"
+	if !crcValid {
+		status = "bad crc"
+	}
+	fmt.Printf("%s %-18s %-22s %-7s %s\n",
+		time.Now().Format("15:04:05.000"),
+		frame.Address.String(),
+		frame.Type().String(),
+		status,
+		hex.EncodeToString(frame.Payload()),
+	)
+}
+
+func printAges(c *crsf.CRSF) {
+	data := c.GetData()
+	stats := c.Stats()
+	fmt.Printf("status: %s\n", c.Status())
+	for frameType, status := range data.Received {
+		fmt.Printf("%-22s count: %-8d age: %-10s rate: %.1fhz\n", frameType.String(), status.Count, time.Since(status.Time).Round(time.Millisecond), stats.FrameRate[frameType])
+	}
+	if c.InFailsafe() {
+		fmt.Println("FAILSAFE")
+	}
+	fmt.Println()
+	printStats(stats)
+}
+
+func printStats(stats crsf.Stats) {
+	fmt.Printf("bytes: %d frames: %d crc errors: %d length errors: %d unknown: %d resyncs: %d dropped reads: %d written: %d write errors: %d\n",
+		stats.BytesRead,
+		stats.TotalFramesDecoded(),
+		stats.CRCErrors,
+		stats.LengthErrors,
+		stats.UnknownTypes,
+		stats.Resyncs,
+		stats.DroppedReads,
+		stats.FramesWritten,
+		stats.WriteErrors,
+	)
+}
+
+func describe(data any) string {
+	switch d := data.(type) {
+	case frames.ChannelsData:
+		return d.String()
+	case frames.ChannelSubSetData:
+		return d.String()
+	case frames.GpsData:
+		return d.String()
+	case frames.VarioData:
+		return d.String()
+	case frames.BatterySensorData:
+		return d.String()
+	case frames.BarometerData:
+		return d.String()
+	case frames.LinkStatsData:
+		return d.String()
+	case frames.LinkRxData:
+		return d.String()
+	case frames.LinkTxData:
+		return d.String()
+	case frames.AttitudeData:
+		return d.String()
+	case frames.FlightModeData:
+		return d.String()
+	case frames.ExtendedFrame:
+		if d.Type == frames.FrameTypeCommand {
+			command, err := frames.UnmarshalCommandFrame(d)
+			if err != nil {
+				return fmt.Sprintf("%s (%s)", d.String(), err)
+			}
+			if ack, err := frames.UnmarshalCommandAck(command); err == nil {
+				return fmt.Sprintf("Destination: %s Origin: %s Ack %s", d.Destination.String(), d.Origin.String(), ack.String())
+			}
+			return fmt.Sprintf("Destination: %s Origin: %s %s", d.Destination.String(), d.Origin.String(), command.String())
+		}
+		return d.String()
+	case crsf.FailsafeEvent:
+		return fmt.Sprintf("Failsafe: %t FrameType: %s LastReceived: %s", d.Active, d.FrameType.String(), d.LastReceived.Format("15:04:05.000"))
+	default:
+		return fmt.Sprintf("%v", d)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Speshl/go-crsf"
+	"github.com/Speshl/go-crsf/frames"
+)
+
+func runSend(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("send", flag.ContinueOnError)
+	port := addPortFlags(fs)
+	channels := fs.String("channels", "", "comma separated channel values in microseconds, e.g. 1500,1500,988,1500")
+	gps := fs.String("gps", "", "latitude,longitude,altitude meters,satellites")
+	battery := fs.String("battery", "", "volts,amps,used mAh,remaining percent")
+	attitude := fs.String("attitude", "", "pitch,roll,yaw in degrees")
+	flightMode := fs.String("flightmode", "", "flight mode name")
+	rate := fs.Duration("rate", 100*time.Millisecond, "telemetry interval")
+	interval := fs.Duration("interval", 5*time.Millisecond, "channels interval")
+	duration := fs.Duration("duration", 0, "how long to send for, 0 sends until interrupted")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	opts := append(port.options(),
+		crsf.WithWriterInterval(*interval),
+		crsf.WithWriteChannels(*channels != ""),
+		crsf.WithReadChannels(false),
+	)
+
+	data := crsf.NewCRSFData()
+	if *channels != "" {
+		values, err := parseFloats(*channels, -1)
+		if err != nil {
+			return fmt.Errorf("invalid channels: %w", err)
+		}
+		for i := range frames.MaxChannels {
+			data.Channels.SetTicks(i, frames.ChannelsMid)
+		}
+		for i, us := range values {
+			data.Channels.SetMicroseconds(i, us)
+		}
+	}
+
+	if *gps != "" {
+		values, err := parseFloats(*gps, 4)
+		if err != nil {
+			return fmt.Errorf("invalid gps: %w", err)
+		}
+		data.Gps = frames.NewGpsData(values[0], values[1], 0, 0, values[2], uint8(values[3]))
+		opts = append(opts, crsf.WithTelemetryRate(frames.FrameTypeGPS, *rate))
+	}
+
+	if *battery != "" {
+		values, err := parseFloats(*battery, 4)
+		if err != nil {
+			return fmt.Errorf("invalid battery: %w", err)
+		}
+		data.BatterySensor = frames.NewBatterySensorData(values[0], values[1], int32(values[2]), int8(values[3]))
+		opts = append(opts, crsf.WithTelemetryRate(frames.FrameTypeBatterySensor, *rate))
+	}
+
+	if *attitude != "" {
+		values, err := parseFloats(*attitude, 3)
+		if err != nil {
+			return fmt.Errorf("invalid attitude: %w", err)
+		}
+		data.Attitude = frames.NewAttitudeData(values[0], values[1], values[2])
+		opts = append(opts, crsf.WithTelemetryRate(frames.FrameTypeAttitude, *rate))
+	}
+
+	if *flightMode != "" {
+		data.FlightMode = frames.NewFlightModeData(*flightMode)
+		opts = append(opts, crsf.WithTelemetryRate(frames.FrameTypeFlightMode, *rate))
+	}
+
+	c := crsf.NewCRSF(port.path, opts...)
+	c.SetData(data)
+
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	fmt.Fprintf(os.Stderr, "sending to %s, interrupt to stop\n", port.path)
+	return wait(ctx, start(ctx, c))
+}
+
+// parseFloats parses comma separated values, count of -1 accepts any number of values
+func parseFloats(value string, count int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if count >= 0 && len(parts) != count {
+		return nil, fmt.Errorf("expected %d values, got %d", count, len(parts))
+	}
+
+	values := make([]float64, len(parts))
+	for i := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(parts[i]), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
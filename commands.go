@@ -0,0 +1,129 @@
+package crsf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+var (
+	ErrCommandTimeout     = errors.New("timed out waiting for command reply")
+	ErrCommandNotHandled  = errors.New("command not handled by device")
+	ErrCommandUnsupported = errors.New("command can not be sent with SendCommand")
+)
+
+// CommandHandler is called from the read parser for each COMMAND frame with a valid inner crc.
+// The command payload is only valid for the duration of the call.
+type CommandHandler func(origin frames.AddressType, command frames.CommandData)
+
+// SendCommand sends a command to device and waits for its ACK, resending after CommandTimeout up to CommandRetries times.
+// If the device answers that it did not act on the command ErrCommandNotHandled is returned with the ACK.
+func (c *CRSF) SendCommand(ctx context.Context, device frames.AddressType, command frames.CommandData) (frames.CommandAckData, error) {
+	if command.Realm == frames.CommandRealmACK {
+		return frames.CommandAckData{}, fmt.Errorf("%w: acks are not acknowledged, use QueueCommand", ErrCommandUnsupported)
+	}
+
+	acks := make(chan frames.CommandAckData, 1)
+	remove := c.HandleCommand(func(origin frames.AddressType, received frames.CommandData) {
+		if origin != device || !received.IsAck(command.Realm, command.Command) {
+			return
+		}
+		ack, err := frames.UnmarshalCommandAck(received)
+		if err != nil {
+			return
+		}
+		select {
+		case acks <- ack:
+		default:
+		}
+	})
+	defer remove()
+
+	for attempt := 0; attempt <= c.opts.CommandRetries; attempt++ {
+		err := c.QueueCommand(device, command)
+		if err != nil {
+			return frames.CommandAckData{}, err
+		}
+
+		timer := time.NewTimer(c.opts.CommandTimeout)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return frames.CommandAckData{}, ctx.Err()
+		case ack := <-acks:
+			timer.Stop()
+			if !ack.Action {
+				return ack, fmt.Errorf("%w: %s", ErrCommandNotHandled, ack.String())
+			}
+			return ack, nil
+		case <-timer.C:
+			continue
+		}
+	}
+	return frames.CommandAckData{}, fmt.Errorf("%w: %s", ErrCommandTimeout, command.String())
+}
+
+// QueueCommand sends a command to device without waiting for an answer
+func (c *CRSF) QueueCommand(device frames.AddressType, command frames.CommandData) error {
+	err := c.SendExtendedFrame(frames.NewCommandFrame(device, c.opts.Address, command))
+	if err != nil {
+		return fmt.Errorf("failed sending command: %w", err)
+	}
+	return nil
+}
+
+// RequestCommand sends a command to device and waits for the reply command of the given realm and command,
+// for commands answered with something other than an ACK such as a speed proposal.
+// The request is resent after CommandTimeout up to CommandRetries times.
+func (c *CRSF) RequestCommand(ctx context.Context, device frames.AddressType, command frames.CommandData, replyRealm frames.CommandRealm, replyCommand uint8) (frames.CommandData, error) {
+	replies := make(chan frames.CommandData, 1)
+	remove := c.HandleCommand(func(origin frames.AddressType, received frames.CommandData) {
+		if origin != device || received.Realm != replyRealm || received.Command != replyCommand {
+			return
+		}
+		received.Payload = slices.Clone(received.Payload)
+		select {
+		case replies <- received:
+		default:
+		}
+	})
+	defer remove()
+
+	for attempt := 0; attempt <= c.opts.CommandRetries; attempt++ {
+		err := c.QueueCommand(device, command)
+		if err != nil {
+			return frames.CommandData{}, err
+		}
+
+		timer := time.NewTimer(c.opts.CommandTimeout)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return frames.CommandData{}, ctx.Err()
+		case reply := <-replies:
+			timer.Stop()
+			return reply, nil
+		case <-timer.C:
+			continue
+		}
+	}
+	return frames.CommandData{}, fmt.Errorf("%w: %s", ErrCommandTimeout, command.String())
+}
+
+// HandleCommand calls handler for every command sent to Address or broadcast.
+// The returned function removes the handler.
+func (c *CRSF) HandleCommand(handler CommandHandler) func() {
+	return c.HandleExtendedFrame(frames.FrameTypeCommand, c.opts.Address, func(frame frames.ExtendedFrame) {
+		command, err := frames.UnmarshalCommandFrame(frame)
+		if err != nil {
+			slog.Debug("dropping command frame", "origin", frame.Origin, "error", err)
+			return
+		}
+		handler(frame.Origin, command)
+	})
+}
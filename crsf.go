@@ -2,12 +2,11 @@ package crsf
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/albenik/go-serial/v2"
+	"github.com/Speshl/go-crsf/frames"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -15,24 +14,68 @@ type CRSF struct {
 	path string
 	opts CRSFOptions
 
-	port        *serial.Port
-	readChan    chan []byte // for testing purposes
-	readBuff    []byte      // buffer for reading from serial port
-	readBuffIdx int
+	transport Transport
+	readChan  chan []byte // chunks read from the transport, decoded by the read parser
+	readFree  chan []byte // empty read buffers, recycled so reads do not allocate
 
-	ctx       context.Context
+	ctx       context.Context // context of the current session
 	crsfGroup *errgroup.Group
 
+	runLock   sync.Mutex
+	runCancel context.CancelFunc
+	runDone   chan struct{} // closed when Run returns, nil when not running
+	status    atomic.Int32
+
 	dataLock sync.RWMutex
 	data     CRSFData
+	failsafe bool
+
+	subscriptionsLock sync.RWMutex
+	subscriptions     []*Subscription
+
+	handlersLock     sync.RWMutex
+	extendedHandlers []*extendedHandlerEntry // in registration order
+	rawHandlersLock  sync.RWMutex
+	rawHandlers      []*rawHandlerEntry
+
+	registry *frames.Registry
+
+	writeQueue chan queuedFrame
+
+	devicesLock sync.RWMutex
+	devices     map[frames.AddressType]Device
+
+	parameterLock sync.Mutex // one parameter transaction at a time
+
+	stats *linkStats
+
+	transportLock    sync.Mutex // held while writing a frame or changing the baud rate
+	baudRate         int
+	fallbackBaudRate int // rate to return to if sync is lost after a negotiated change, 0 when there is none
+	baudRateChanged  time.Time
 }
 
 // NewCRSF("somepath", WithBaudRate(115200), WithTimeout(1000))
+// NewCRSF("tcp-bridge", WithTransport(conn))
 func NewCRSF(path string, opts ...Option) *CRSF {
-	return &CRSF{
+	c := &CRSF{
 		path: path,
 		opts: getOptions(opts),
+		data: NewCRSFData(),
+
+		writeQueue: make(chan queuedFrame, 64),
+
+		devices: make(map[frames.AddressType]Device),
+
+		stats: newLinkStats(),
+	}
+	c.baudRate = c.opts.BaudRate
+	c.registry = c.opts.Registry
+	if c.registry == nil {
+		c.registry = frames.NewRegistry()
 	}
+	c.registerDiscoveryHandlers()
+	return c
 }
 
 func (c *CRSF) String() string {
@@ -41,44 +84,16 @@ func (c *CRSF) String() string {
 	return c.data.String()
 }
 
+// Stop closes a running CRSF.
+//
+// Deprecated: use Close
 func (c *CRSF) Stop() {
-	c.ctx.Done()
+	_ = c.Close()
 }
 
+// Start runs the CRSF until ctx is done.
+//
+// Deprecated: use Run
 func (c *CRSF) Start(ctx context.Context) error {
-	var err error
-
-	c.port, err = serial.Open(c.path,
-		serial.WithBaudrate(c.opts.BaudRate),
-		serial.WithDataBits(8),
-		serial.WithParity(serial.NoParity),
-		serial.WithStopBits(serial.OneStopBit),
-		serial.WithReadTimeout(c.opts.ReadTimeout),
-	)
-	if err != nil {
-		return fmt.Errorf("failed opening crsf %s: %w", c.path, err)
-	}
-
-	crsfGroup, groupCtx := errgroup.WithContext(ctx)
-	c.ctx = groupCtx
-
-	c.readChan = make(chan []byte, 1024)
-
-	c.crsfGroup.Go(c.startReader)
-	c.crsfGroup.Go(c.startReadParser)
-
-	if !c.opts.ReadOnly {
-		c.crsfGroup.Go(c.startWriter)
-	}
-
-	crsfGroup.Wait()
-	if err := c.crsfGroup.Wait(); err != nil {
-		if errors.Is(err, context.Canceled) {
-			slog.Info("crsf context was cancelled", "path", c.path)
-			return nil
-		}
-		return fmt.Errorf("crsf group error: %w", err)
-	}
-
-	return nil
+	return c.Run(ctx)
 }
@@ -2,30 +2,67 @@ package crsf
 
 import (
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Speshl/go-crsf/frames"
 )
 
 type CRSFData struct {
-	Channels frames.ChannelsData
+	Channels frames.ChannelsData `json:"channels"`
 	CRSFTelemetry
+
+	Received map[frames.FrameType]FrameStatus `json:"received"` // when each frame type last arrived
+
+	Custom map[frames.FrameType]frames.Frame `json:"custom,omitempty"` // latest frame of each type decoded by a decoder added with RegisterFrame
+}
+
+// FrameStatus tracks arrivals of a single frame type
+type FrameStatus struct {
+	Time  time.Time `json:"time"`  // when the last frame was received
+	Count uint64    `json:"count"` // number of frames received
 }
 
 type CRSFTelemetry struct {
-	Gps           frames.GpsData
-	Vario         frames.VarioData
-	BatterySensor frames.BatterySensorData
-	Barometer     frames.BarometerData
-	LinkStats     frames.LinkStatsData
-	LinkRx        frames.LinkRxData
-	LinkTx        frames.LinkTxData
-	Attitude      frames.AttitudeData
-	FlightMode    frames.FlightModeData
+	Gps           frames.GpsData           `json:"gps"`
+	Vario         frames.VarioData         `json:"vario"`
+	BatterySensor frames.BatterySensorData `json:"battery_sensor"`
+	Barometer     frames.BarometerData     `json:"barometer"`
+	LinkStats     frames.LinkStatsData     `json:"link_stats"`
+	LinkRx        frames.LinkRxData        `json:"link_rx"`
+	LinkTx        frames.LinkTxData        `json:"link_tx"`
+	Attitude      frames.AttitudeData      `json:"attitude"`
+	FlightMode    frames.FlightModeData    `json:"flight_mode"`
 }
 
 func NewCRSFData() CRSFData {
-	return CRSFData{}
+	return CRSFData{
+		Received: make(map[frames.FrameType]FrameStatus),
+	}
+}
+
+// Age is how long ago the frame type was last received. Frame types never received are infinitely old.
+func (d *CRSFData) Age(frameType frames.FrameType) time.Duration {
+	status, ok := d.Received[frameType]
+	if !ok || status.Count == 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Since(status.Time)
+}
+
+// IsStale reports if the frame type has not been received within maxAge
+func (d *CRSFData) IsStale(frameType frames.FrameType, maxAge time.Duration) bool {
+	return d.Age(frameType) > maxAge
+}
+
+func (d *CRSFData) clone() CRSFData {
+	data := *d
+	data.Received = maps.Clone(d.Received)
+	data.Custom = maps.Clone(d.Custom)
+	return data
 }
 
 func (d *CRSFData) String() string {
@@ -40,5 +77,8 @@ func (d *CRSFData) String() string {
 	fmt.Fprintf(&sb, "LinkTx: {%s}\n", d.LinkTx.String())
 	fmt.Fprintf(&sb, "Attitude: {%s}\n", d.Attitude.String())
 	fmt.Fprintf(&sb, "FlightMode: {%s}", d.FlightMode.String())
+	for _, frameType := range slices.Sorted(maps.Keys(d.Custom)) {
+		fmt.Fprintf(&sb, "\n%s: {%v}", frameType.String(), d.Custom[frameType])
+	}
 	return sb.String()
 }
@@ -0,0 +1,112 @@
+package crsf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// Device is a CRSF device that answered a DEVICE_PING
+type Device struct {
+	Address  frames.AddressType
+	Info     frames.DeviceInfoData
+	LastSeen time.Time
+}
+
+// Discover broadcasts a DEVICE_PING and collects DEVICE_INFO responses until ctx is done.
+// Use a context with a timeout, devices normally answer within a few hundred milliseconds.
+func (c *CRSF) Discover(ctx context.Context) ([]Device, error) {
+	var foundLock sync.Mutex
+	found := make(map[frames.AddressType]Device)
+
+	remove := c.HandleExtendedFrame(frames.FrameTypeDeviceInfo, c.opts.Address, func(frame frames.ExtendedFrame) {
+		info, err := frames.UnmarshalDeviceInfo(frame.Payload)
+		if err != nil {
+			return
+		}
+		foundLock.Lock()
+		defer foundLock.Unlock()
+		found[frame.Origin] = Device{Address: frame.Origin, Info: info, LastSeen: time.Now()}
+	})
+	defer remove()
+
+	err := c.Ping(frames.AddressTypeBroadcast)
+	if err != nil {
+		return nil, err
+	}
+
+	<-ctx.Done()
+
+	foundLock.Lock()
+	defer foundLock.Unlock()
+	return sortDevices(found), nil
+}
+
+// Ping sends a DEVICE_PING to the destination, responses are available from Devices
+func (c *CRSF) Ping(destination frames.AddressType) error {
+	err := c.SendExtendedFrame(frames.ExtendedFrame{
+		Type:        frames.FrameTypeDevicePing,
+		Destination: destination,
+		Origin:      c.opts.Address,
+	})
+	if err != nil {
+		return fmt.Errorf("failed sending device ping: %w", err)
+	}
+	return nil
+}
+
+// Devices returns every device that has sent a DEVICE_INFO
+func (c *CRSF) Devices() []Device {
+	c.devicesLock.RLock()
+	defer c.devicesLock.RUnlock()
+	return sortDevices(c.devices)
+}
+
+func (c *CRSF) registerDiscoveryHandlers() {
+	c.HandleExtendedFrame(frames.FrameTypeDeviceInfo, frames.AddressTypeBroadcast, c.handleDeviceInfo)
+
+	if c.opts.DeviceInfo != nil {
+		c.HandleExtendedFrame(frames.FrameTypeDevicePing, c.opts.Address, c.handleDevicePing)
+	}
+}
+
+func (c *CRSF) handleDeviceInfo(frame frames.ExtendedFrame) {
+	info, err := frames.UnmarshalDeviceInfo(frame.Payload)
+	if err != nil {
+		slog.Warn("failed parsing device info", "error", err, "origin", frame.Origin)
+		return
+	}
+
+	c.devicesLock.Lock()
+	defer c.devicesLock.Unlock()
+	c.devices[frame.Origin] = Device{Address: frame.Origin, Info: info, LastSeen: time.Now()}
+}
+
+// handleDevicePing answers pings when emulating a device
+func (c *CRSF) handleDevicePing(frame frames.ExtendedFrame) {
+	err := c.SendExtendedFrame(frames.ExtendedFrame{
+		Type:        frames.FrameTypeDeviceInfo,
+		Destination: frame.Origin,
+		Origin:      c.opts.Address,
+		Payload:     c.opts.DeviceInfo.MarshalDeviceInfo(),
+	})
+	if err != nil {
+		slog.Warn("failed answering device ping", "error", err, "origin", frame.Origin)
+	}
+}
+
+func sortDevices(devices map[frames.AddressType]Device) []Device {
+	sorted := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		sorted = append(sorted, device)
+	}
+	slices.SortFunc(sorted, func(a, b Device) int {
+		return int(a.Address) - int(b.Address)
+	})
+	return sorted
+}
@@ -0,0 +1,174 @@
+package crsf
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+const DefaultSubscriptionBuffer = 64
+
+// EventKind tells decoded frames apart from link events
+type EventKind int
+
+const (
+	EventKindFrame    EventKind = iota // Data is the decoded frame of Type
+	EventKindFailsafe                  // Data is a FailsafeEvent, Type and Raw are not set
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventKindFrame:
+		return "frame"
+	case EventKindFailsafe:
+		return "failsafe"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is published to subscribers for every decoded frame and for failsafe changes
+type Event struct {
+	Kind EventKind
+	Type frames.FrameType
+	Time time.Time
+	Data any    // decoded value, e.g. frames.GpsData for FrameTypeGPS
+	Raw  []byte // type + payload + crc as received
+}
+
+// MarshalJSON encodes the event with its kind, the frame type name and id and the raw frame as hex.
+// The type is left out of events that are not frames.
+func (e Event) MarshalJSON() ([]byte, error) {
+	var frameType *frames.FrameType
+	var typeID *uint8
+	if e.Kind == EventKindFrame {
+		id := uint8(e.Type)
+		frameType = &e.Type
+		typeID = &id
+	}
+	return json.Marshal(struct {
+		Time   time.Time         `json:"time"`
+		Kind   string            `json:"kind"`
+		Type   *frames.FrameType `json:"type,omitempty"`
+		TypeID *uint8            `json:"type_id,omitempty"`
+		Data   any               `json:"data"`
+		Raw    string            `json:"raw,omitempty"`
+	}{
+		Time:   e.Time,
+		Kind:   e.Kind.String(),
+		Type:   frameType,
+		TypeID: typeID,
+		Data:   e.Data,
+		Raw:    hex.EncodeToString(e.Raw),
+	})
+}
+
+// Subscription receives events for the frame types it was created with.
+// Events are delivered on a buffered channel. If the subscriber falls behind and the buffer is full
+// new events are dropped (the oldest buffered events are kept) and counted in Dropped.
+type Subscription struct {
+	C <-chan Event
+
+	events  chan Event
+	types   []frames.FrameType
+	dropped atomic.Uint64
+
+	closeOnce sync.Once
+	crsf      *CRSF
+}
+
+// Subscribe returns a subscription for the given frame types, or all frame types if none are given.
+// Failsafe events are only delivered to subscriptions for all frame types.
+// A bufferSize of 0 or less uses DefaultSubscriptionBuffer.
+// Call Unsubscribe when finished to release the subscription.
+func (c *CRSF) Subscribe(bufferSize int, types ...frames.FrameType) *Subscription {
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriptionBuffer
+	}
+
+	events := make(chan Event, bufferSize)
+	sub := &Subscription{
+		C:      events,
+		events: events,
+		types:  slices.Clone(types),
+		crsf:   c,
+	}
+
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+	c.subscriptions = append(c.subscriptions, sub)
+	return sub
+}
+
+// Unsubscribe stops delivery and closes C
+func (s *Subscription) Unsubscribe() {
+	s.closeOnce.Do(func() {
+		s.crsf.subscriptionsLock.Lock()
+		defer s.crsf.subscriptionsLock.Unlock()
+		s.crsf.subscriptions = slices.DeleteFunc(s.crsf.subscriptions, func(sub *Subscription) bool {
+			return sub == s
+		})
+		close(s.events)
+	})
+}
+
+// Dropped is the number of events dropped because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// wants reports if the subscription receives event, events that are not frames only go to subscriptions for every type
+func (s *Subscription) wants(event Event) bool {
+	if event.Kind != EventKindFrame {
+		return len(s.types) == 0
+	}
+	return len(s.types) == 0 || slices.Contains(s.types, event.Type)
+}
+
+func (c *CRSF) publish(frameType frames.FrameType, raw []byte, data any) {
+	c.subscriptionsLock.RLock()
+	defer c.subscriptionsLock.RUnlock()
+
+	if len(c.subscriptions) == 0 {
+		return
+	}
+
+	c.deliver(Event{
+		Kind: EventKindFrame,
+		Type: frameType,
+		Time: time.Now(),
+		Data: data,
+		Raw:  slices.Clone(raw),
+	})
+}
+
+func (c *CRSF) publishFailsafe(event FailsafeEvent) {
+	c.subscriptionsLock.RLock()
+	defer c.subscriptionsLock.RUnlock()
+
+	c.deliver(Event{
+		Kind: EventKindFailsafe,
+		Time: time.Now(),
+		Data: event,
+	})
+}
+
+// deliver sends event to every subscription that wants it, subscriptionsLock must be held
+func (c *CRSF) deliver(event Event) {
+	for _, sub := range c.subscriptions {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package exporter publishes CRSF link, telemetry and parser metrics in the Prometheus text format.
+//
+//	exp := exporter.New(c)
+//	go exp.Run(ctx)
+//	http.Handle("/metrics", exp)
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Speshl/go-crsf"
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// frameTypes are the frames the exporter keeps the latest value of
+var frameTypes = []frames.FrameType{
+	frames.FrameTypeLinkStats,
+	frames.FrameTypeLinkRx,
+	frames.FrameTypeLinkTx,
+	frames.FrameTypeBatterySensor,
+	frames.FrameTypeGPS,
+	frames.FrameTypeAttitude,
+	frames.FrameTypeBarometer,
+	frames.FrameTypeVario,
+}
+
+type Exporter struct {
+	crsf *crsf.CRSF
+	opts ExporterOptions
+
+	lock    sync.RWMutex
+	latest  map[frames.FrameType]any
+	updated map[frames.FrameType]time.Time
+}
+
+type ExporterOptions struct {
+	Namespace  string // prefix of every metric name
+	BufferSize int    // subscription buffer, 0 uses crsf.DefaultSubscriptionBuffer
+}
+
+type Option func(*ExporterOptions)
+
+func WithNamespace(namespace string) Option {
+	return func(o *ExporterOptions) {
+		o.Namespace = namespace
+	}
+}
+
+func WithBufferSize(size int) Option {
+	return func(o *ExporterOptions) {
+		o.BufferSize = size
+	}
+}
+
+// New exports metrics for c, call Run to start collecting telemetry
+func New(c *crsf.CRSF, opts ...Option) *Exporter {
+	options := ExporterOptions{
+		Namespace: "crsf",
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Exporter{
+		crsf:    c,
+		opts:    options,
+		latest:  make(map[frames.FrameType]any),
+		updated: make(map[frames.FrameType]time.Time),
+	}
+}
+
+// Run keeps the latest telemetry from the frame stream until ctx is done
+func (e *Exporter) Run(ctx context.Context) error {
+	sub := e.crsf.Subscribe(e.opts.BufferSize, frameTypes...)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			e.lock.Lock()
+			e.latest[event.Type] = event.Data
+			e.updated[event.Type] = event.Time
+			e.lock.Unlock()
+		}
+	}
+}
+
+// ServeHTTP serves the metrics, mount it on /metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buff bytes.Buffer
+	err := e.WriteMetrics(&buff)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buff.Bytes())
+}
+
+// WriteMetrics writes every metric in the Prometheus text format, telemetry that has not been received is left out
+func (e *Exporter) WriteMetrics(w io.Writer) error {
+	m := &metricWriter{
+		w:         w,
+		namespace: e.opts.Namespace,
+	}
+
+	e.lock.RLock()
+	latest := make(map[frames.FrameType]any, len(e.latest))
+	updated := make(map[frames.FrameType]time.Time, len(e.updated))
+	for frameType, data := range e.latest {
+		latest[frameType] = data
+		updated[frameType] = e.updated[frameType]
+	}
+	e.lock.RUnlock()
+
+	for _, frameType := range frameTypes {
+		data, ok := latest[frameType]
+		if !ok {
+			continue
+		}
+		switch d := data.(type) {
+		case frames.LinkStatsData:
+			writeLinkStats(m, d)
+		case frames.LinkRxData:
+			m.write("link_rx_rssi_percent", gauge, "Downlink RSSI reported by the receiver.", value(float64(d.RssiPercent)))
+		case frames.LinkTxData:
+			writeLinkTx(m, d)
+		case frames.BatterySensorData:
+			writeBattery(m, d)
+		case frames.GpsData:
+			writeGps(m, d)
+		case frames.AttitudeData:
+			writeAttitude(m, d)
+		case frames.BarometerData:
+			m.write("barometer_altitude_meters", gauge, "Barometric altitude.", value(d.AltitudeMeters()))
+			m.write("barometer_vertical_speed_meters_per_second", gauge, "Barometric vertical speed.", value(d.VerticalSpeed()))
+		case frames.VarioData:
+			m.write("vario_vertical_speed_meters_per_second", gauge, "Vario vertical speed.", value(d.VerticalSpeed()))
+		}
+	}
+
+	lastReceived := make([]sample, 0, len(updated))
+	for _, frameType := range frameTypes {
+		if t, ok := updated[frameType]; ok {
+			lastReceived = append(lastReceived, labeled(float64(t.UnixNano())/1e9, "type", frameType.String()))
+		}
+	}
+	m.write("telemetry_last_received_timestamp_seconds", gauge, "Unix time the telemetry frame was last received.", lastReceived...)
+
+	failsafe := 0.0
+	if e.crsf.InFailsafe() {
+		failsafe = 1
+	}
+	m.write("failsafe", gauge, "1 while the link is in failsafe.", value(failsafe))
+
+	writeStats(m, e.crsf.Stats())
+	return m.err
+}
+
+func writeLinkStats(m *metricWriter, d frames.LinkStatsData) {
+	m.write("link_uplink_rssi_dbm", gauge, "Uplink RSSI per antenna.",
+		labeled(float64(d.Rssi1DBm()), "antenna", "1"),
+		labeled(float64(d.Rssi2DBm()), "antenna", "2"),
+	)
+	m.write("link_uplink_quality_percent", gauge, "Uplink link quality.", value(float64(d.UplinkQuality)))
+	m.write("link_uplink_snr_db", gauge, "Uplink signal to noise ratio.", value(float64(d.UplinkSnrDB())))
+	m.write("link_active_antenna", gauge, "Diversity antenna in use.", value(float64(d.ActiveAntenna())))
+	m.write("link_rf_mode", gauge, "RF mode, the meaning depends on the receiver.", value(float64(d.RfMode)))
+	m.write("link_tx_power_milliwatts", gauge, "Uplink transmit power.", value(float64(d.TxPowerMilliwatts())))
+	m.write("link_downlink_rssi_dbm", gauge, "Downlink RSSI.", value(float64(d.DownlinkRssiDBm())))
+	m.write("link_downlink_quality_percent", gauge, "Downlink link quality.", value(float64(d.DownlinkQuality)))
+	m.write("link_downlink_snr_db", gauge, "Downlink signal to noise ratio.", value(float64(d.DownlinkSnrDB())))
+}
+
+func writeLinkTx(m *metricWriter, d frames.LinkTxData) {
+	m.write("link_tx_rssi_percent", gauge, "Uplink RSSI reported by the transmitter.", value(float64(d.RssiPercent)))
+	m.write("link_tx_packet_rate_hz", gauge, "Packet rate reported by the transmitter.", value(float64(d.PacketRateHz())))
+}
+
+func writeBattery(m *metricWriter, d frames.BatterySensorData) {
+	m.write("battery_volts", gauge, "Battery voltage.", value(d.Volts()))
+	m.write("battery_amps", gauge, "Battery current.", value(d.Amps()))
+	m.write("battery_used_mah", gauge, "Battery capacity used.", value(float64(d.UsedMah())))
+	m.write("battery_remaining_percent", gauge, "Battery remaining.", value(float64(d.RemainingPercent())))
+}
+
+func writeGps(m *metricWriter, d frames.GpsData) {
+	m.write("gps_latitude_degrees", gauge, "GPS latitude.", value(d.Latitude()))
+	m.write("gps_longitude_degrees", gauge, "GPS longitude.", value(d.Longitude()))
+	m.write("gps_altitude_meters", gauge, "GPS altitude.", value(d.AltitudeMeters()))
+	m.write("gps_speed_kph", gauge, "GPS ground speed.", value(d.SpeedKph()))
+	m.write("gps_course_degrees", gauge, "GPS course over ground.", value(d.CourseDegrees()))
+	m.write("gps_satellites", gauge, "GPS satellites in view.", value(float64(d.SatelliteCount)))
+}
+
+func writeAttitude(m *metricWriter, d frames.AttitudeData) {
+	m.write("attitude_degrees", gauge, "Attitude per axis.",
+		labeled(d.PitchDegree(), "axis", "pitch"),
+		labeled(d.RollDegree(), "axis", "roll"),
+		labeled(d.YawDegree(), "axis", "yaw"),
+	)
+}
+
+func writeStats(m *metricWriter, stats crsf.Stats) {
+	m.write("bytes_read_total", counter, "Bytes read from the transport.", value(float64(stats.BytesRead)))
+	m.write("frames_decoded_total", counter, "Frames decoded per frame type.", byFrameType(stats.FramesDecoded)...)
+	m.write("frame_rate_hz", gauge, "Frames per second per frame type.", byFrameType(stats.FrameRate)...)
+	m.write("crc_errors_total", counter, "Frames that failed CRC validation.", value(float64(stats.CRCErrors)))
+	m.write("length_errors_total", counter, "Frames with an invalid length.", value(float64(stats.LengthErrors)))
+	m.write("unknown_frame_types_total", counter, "Frames with an unsupported frame type.", value(float64(stats.UnknownTypes)))
+	m.write("resyncs_total", counter, "Times bytes were skipped looking for a sync byte.", value(float64(stats.Resyncs)))
+	m.write("dropped_reads_total", counter, "Read chunks dropped because the parser fell behind.", value(float64(stats.DroppedReads)))
+	m.write("frames_written_total", counter, "Frames written to the transport.", value(float64(stats.FramesWritten)))
+	m.write("write_errors_total", counter, "Failed writes to the transport.", value(float64(stats.WriteErrors)))
+}
+
+func byFrameType[T uint64 | float64](values map[frames.FrameType]T) []sample {
+	keys := make([]frames.FrameType, 0, len(values))
+	for frameType := range values {
+		keys = append(keys, frameType)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	samples := make([]sample, 0, len(keys))
+	for _, frameType := range keys {
+		samples = append(samples, labeled(float64(values[frameType]), "type", frameType.String(), "type_id", strconv.Itoa(int(frameType))))
+	}
+	return samples
+}
@@ -0,0 +1,96 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metricKind string
+
+const (
+	gauge   metricKind = "gauge"
+	counter metricKind = "counter"
+)
+
+type sample struct {
+	labels []string // name, value pairs
+	value  float64
+}
+
+func value(v float64) sample {
+	return sample{value: v}
+}
+
+func labeled(v float64, labels ...string) sample {
+	return sample{labels: labels, value: v}
+}
+
+// metricWriter writes the Prometheus text exposition format, the first error is kept and later writes are skipped
+type metricWriter struct {
+	w         io.Writer
+	namespace string
+	err       error
+}
+
+func (m *metricWriter) write(name string, kind metricKind, help string, samples ...sample) {
+	if m.err != nil || len(samples) == 0 {
+		return
+	}
+
+	fullName := name
+	if m.namespace != "" {
+		fullName = m.namespace + "_" + name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", fullName, help)
+	fmt.Fprintf(&b, "# TYPE %s %s\n", fullName, kind)
+	for _, s := range samples {
+		b.WriteString(fullName)
+		writeLabels(&b, s.labels)
+		b.WriteByte(' ')
+		b.WriteString(formatValue(s.value))
+		b.WriteByte('\n')
+	}
+
+	_, m.err = io.WriteString(m.w, b.String())
+}
+
+func writeLabels(b *strings.Builder, labels []string) {
+	if len(labels) < 2 {
+		return
+	}
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
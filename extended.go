@@ -0,0 +1,104 @@
+package crsf
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+var (
+	ErrReadOnly       = errors.New("crsf is read only")
+	ErrWriteQueueFull = errors.New("write queue is full")
+)
+
+// ExtendedHandler is called from the read parser for each matching extended frame.
+// The frame payload is only valid for the duration of the call.
+type ExtendedHandler func(frame frames.ExtendedFrame)
+
+type extendedHandlerEntry struct {
+	frameType   frames.FrameType
+	destination frames.AddressType
+	handler     ExtendedHandler
+}
+
+// HandleExtendedFrame routes extended frames of the given type and destination to handler.
+// Handlers for a specific destination also receive frames sent to AddressTypeBroadcast.
+// Handlers registered with AddressTypeBroadcast receive every frame of the type regardless of destination.
+// Handlers are called in the order they were registered.
+// The returned function removes the handler.
+func (c *CRSF) HandleExtendedFrame(frameType frames.FrameType, destination frames.AddressType, handler ExtendedHandler) func() {
+	entry := &extendedHandlerEntry{
+		frameType:   frameType,
+		destination: destination,
+		handler:     handler,
+	}
+
+	c.handlersLock.Lock()
+	defer c.handlersLock.Unlock()
+	c.extendedHandlers = append(c.extendedHandlers, entry)
+
+	return func() {
+		c.handlersLock.Lock()
+		defer c.handlersLock.Unlock()
+		c.extendedHandlers = slices.DeleteFunc(c.extendedHandlers, func(e *extendedHandlerEntry) bool {
+			return e == entry
+		})
+	}
+}
+
+// SendExtendedFrame queues an extended frame for the writer.
+// The frame starts with its destination when that is a sync address, otherwise with AddressTypeFlightController.
+func (c *CRSF) SendExtendedFrame(frame frames.ExtendedFrame) error {
+	if !frame.Type.IsExtended() {
+		return fmt.Errorf("frame type %s is not extended", frame.Type.String())
+	}
+	return c.queueFrame(extendedSyncAddress(frame.Destination), frame.Type, frame.MarshalExtendedFrame())
+}
+
+// extendedSyncAddress is the sync byte for an extended frame sent to destination,
+// broadcasts and devices without their own sync byte use AddressTypeFlightController
+func extendedSyncAddress(destination frames.AddressType) frames.AddressType {
+	if destination.IsSync() {
+		return destination
+	}
+	return frames.AddressTypeFlightController
+}
+
+func (c *CRSF) applyExtendedFrame(data []byte) error {
+	frame, err := frames.UnmarshalExtendedFrame(data)
+	if err != nil {
+		return err
+	}
+
+	published := frame
+	published.Payload = slices.Clone(frame.Payload) //subscribers read it after the decoder has reused its buffer
+	c.frameReceived(frame.Type, data, published)
+
+	c.handleExtendedFrame(frame)
+	return nil
+}
+
+func (c *CRSF) handleExtendedFrame(frame frames.ExtendedFrame) {
+	for _, handler := range c.getExtendedHandlers(frame.Type, frame.Destination) {
+		handler(frame)
+	}
+}
+
+func (c *CRSF) getExtendedHandlers(frameType frames.FrameType, destination frames.AddressType) []ExtendedHandler {
+	c.handlersLock.RLock()
+	defer c.handlersLock.RUnlock()
+
+	handlers := make([]ExtendedHandler, 0)
+	for _, entry := range c.extendedHandlers {
+		if entry.frameType != frameType {
+			continue
+		}
+		if entry.destination != destination && entry.destination != frames.AddressTypeBroadcast && destination != frames.AddressTypeBroadcast {
+			continue
+		}
+		handlers = append(handlers, entry.handler)
+	}
+	return handlers
+}
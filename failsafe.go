@@ -0,0 +1,81 @@
+package crsf
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// failsafeFrameTypes stop arriving when the receiver loses its link
+var failsafeFrameTypes = []frames.FrameType{
+	frames.FrameTypeChannels,
+	frames.FrameTypeLinkStats,
+}
+
+// FailsafeEvent is published as the Data of an EventKindFailsafe Event when a watched frame type goes stale or recovers
+type FailsafeEvent struct {
+	FrameType    frames.FrameType // the watched frame type that changed
+	Active       bool             // true when the frame type went stale, false when it is arriving again
+	LastReceived time.Time        // when the frame type was last received
+}
+
+func (c *CRSF) startFailsafeMonitor() error {
+	ticker := time.NewTicker(max(c.opts.FailsafeTimeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	stale := make(map[frames.FrameType]bool, len(failsafeFrameTypes))
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-ticker.C:
+			c.checkFailsafe(stale)
+		}
+	}
+}
+
+func (c *CRSF) checkFailsafe(stale map[frames.FrameType]bool) {
+	changes := make([]FailsafeEvent, 0, len(failsafeFrameTypes))
+
+	c.dataLock.Lock()
+	for _, frameType := range failsafeFrameTypes {
+		status := c.data.Received[frameType]
+		if status.Count == 0 {
+			continue // never arrived so there is nothing to lose
+		}
+
+		isStale := c.data.IsStale(frameType, c.opts.FailsafeTimeout)
+		if isStale != stale[frameType] {
+			stale[frameType] = isStale
+			changes = append(changes, FailsafeEvent{FrameType: frameType, Active: isStale, LastReceived: status.Time})
+		}
+	}
+
+	wasFailsafe := c.failsafe
+	c.failsafe = false
+	for _, isStale := range stale {
+		if isStale {
+			c.failsafe = true
+			break
+		}
+	}
+
+	//reapplied on every check so channels set while in failsafe do not replace them
+	if c.failsafe && c.opts.FailsafeChannels != nil {
+		c.data.Channels = frames.ChannelsData{Channels: slices.Clone(c.opts.FailsafeChannels)}
+	}
+	c.dataLock.Unlock()
+
+	if c.failsafe && !wasFailsafe {
+		slog.Warn("crsf entered failsafe", "path", c.path)
+	} else if !c.failsafe && wasFailsafe {
+		slog.Info("crsf left failsafe", "path", c.path)
+	}
+
+	for _, change := range changes {
+		c.publishFailsafe(change)
+	}
+}
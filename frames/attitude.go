@@ -3,7 +3,9 @@ package frames
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 const (
@@ -12,9 +14,18 @@ const (
 
 // All values must be in the +/-180 degree +/-PI radian range
 type AttitudeData struct {
-	Pitch int16 //angle in radians * 10000
-	Roll  int16 //angle in radians * 10000
-	Yaw   int16 //angle in radians * 10000
+	Pitch int16 `json:"pitch"` //angle in radians * 10000
+	Roll  int16 `json:"roll"`  //angle in radians * 10000
+	Yaw   int16 `json:"yaw"`   //angle in radians * 10000
+}
+
+// NewAttitudeData builds attitude telemetry from angles in degrees, angles outside (-180, 180] are wrapped into it
+func NewAttitudeData(pitchDegrees, rollDegrees, yawDegrees float64) AttitudeData {
+	return AttitudeData{
+		Pitch: getFromDegree(pitchDegrees),
+		Roll:  getFromDegree(rollDegrees),
+		Yaw:   getFromDegree(yawDegrees),
+	}
 }
 
 func UnmarshalAttitude(data []byte) (AttitudeData, error) {
@@ -35,6 +46,23 @@ func UnmarshalAttitude(data []byte) (AttitudeData, error) {
 	return d, nil
 }
 
+func (d *AttitudeData) MarshalAttitude() []byte {
+	payload := make([]byte, AttitudeFrameLength-2)
+	binary.BigEndian.PutUint16(payload[0:2], uint16(d.Pitch))
+	binary.BigEndian.PutUint16(payload[2:4], uint16(d.Roll))
+	binary.BigEndian.PutUint16(payload[4:6], uint16(d.Yaw))
+	return payload
+}
+
+func (d *AttitudeData) Type() FrameType {
+	return FrameTypeAttitude
+}
+
+// MarshalPayload implements Frame
+func (d *AttitudeData) MarshalPayload() []byte {
+	return d.MarshalAttitude()
+}
+
 func (d *AttitudeData) String() string {
 	pitch := getAsDegree(d.Pitch)
 	roll := getAsDegree(d.Roll)
@@ -55,6 +83,44 @@ func (d *AttitudeData) YawDegree() float64 {
 	return getAsDegree(d.Yaw)
 }
 
+func (d *AttitudeData) PitchRadians() float64 {
+	return float64(d.Pitch) / 10000
+}
+
+func (d *AttitudeData) RollRadians() float64 {
+	return float64(d.Roll) / 10000
+}
+
+func (d *AttitudeData) YawRadians() float64 {
+	return float64(d.Yaw) / 10000
+}
+
 func getAsDegree(value int16) float64 {
-	return (float64(value) / 10000) * (180 / 3.14159)
+	return (float64(value) / 10000) * (180 / math.Pi)
+}
+
+func getFromDegree(degrees float64) int16 {
+	degrees = math.Mod(degrees, 360)
+	if degrees > 180 {
+		degrees -= 360
+	} else if degrees <= -180 {
+		degrees += 360
+	}
+	return int16(math.Round(degrees * math.Pi / 180 * 10000))
+}
+
+// MarshalJSON includes the raw fields and the angles in degrees
+func (d AttitudeData) MarshalJSON() ([]byte, error) {
+	type raw AttitudeData
+	return json.Marshal(struct {
+		raw
+		PitchDegrees float64 `json:"pitch_deg"`
+		RollDegrees  float64 `json:"roll_deg"`
+		YawDegrees   float64 `json:"yaw_deg"`
+	}{
+		raw:          raw(d),
+		PitchDegrees: d.PitchDegree(),
+		RollDegrees:  d.RollDegree(),
+		YawDegrees:   d.YawDegree(),
+	})
 }
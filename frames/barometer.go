@@ -3,7 +3,9 @@ package frames
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 const (
@@ -15,9 +17,25 @@ type BarometerData struct {
 		If high bit IS NOT set, value is in decimeters + 10000  values between(-1000.0m - 2276.7m)
 		If high bit IS set, value is in meters with values between (0m-32767m)
 	*/
-	Altitude uint16
+	Altitude uint16 `json:"altitude"`
 
-	Speed int16 // cm/s (1.5m/s is 150)
+	Speed int16 `json:"speed"` // cm/s (1.5m/s is 150)
+}
+
+// NewBarometerData builds barometer telemetry from an altitude in meters and vertical speed in m/s.
+// Altitudes from -1000m to 2276.7m are sent in decimeters, higher altitudes in meters.
+func NewBarometerData(altitudeMeters, verticalSpeed float64) BarometerData {
+	d := BarometerData{
+		Speed: int16(math.Round(verticalSpeed * 100)),
+	}
+
+	decimeters := math.Round(altitudeMeters*10) + 10000
+	if decimeters < 0x8000 {
+		d.Altitude = uint16(max(decimeters, 0))
+	} else {
+		d.Altitude = uint16(min(math.Round(altitudeMeters), 0x7FFF)) | 0x8000
+	}
+	return d
 }
 
 func UnmarshalBarometer(data []byte) (BarometerData, error) {
@@ -35,18 +53,53 @@ func UnmarshalBarometer(data []byte) (BarometerData, error) {
 	return d, nil
 }
 
-func (d *BarometerData) String() string {
+func (d *BarometerData) MarshalBarometer() []byte {
+	payload := make([]byte, BarometerFrameLength-2)
+	binary.LittleEndian.PutUint16(payload[0:2], d.Altitude)
+	binary.LittleEndian.PutUint16(payload[2:4], uint16(d.Speed))
+	return payload
+}
 
-	altitude := float32(0)
-	if d.Altitude > 32768 { //highest value a uint16 can have before high bit is set
-		//high bit IS set so value is in meters
-		altitude = float32(d.Altitude) - 32768
-	} else {
-		//high bit IS NOT set so value is in decimeters
-		altitude = (float32(d.Altitude) - 10000) / 10
-	}
+func (d *BarometerData) Type() FrameType {
+	return FrameTypeBarometer
+}
 
+// MarshalPayload implements Frame
+func (d *BarometerData) MarshalPayload() []byte {
+	return d.MarshalBarometer()
+}
+
+func (d *BarometerData) String() string {
+	altitude := d.AltitudeMeters()
 	speed := d.Speed
 
 	return fmt.Sprintf("Altitude: %.1fm Speed: %dcm/s", altitude, speed)
 }
+
+// MarshalJSON includes the raw fields and their values in meters and m/s
+func (d BarometerData) MarshalJSON() ([]byte, error) {
+	type raw BarometerData
+	return json.Marshal(struct {
+		raw
+		AltitudeMeters float64 `json:"altitude_m"`
+		VerticalSpeed  float64 `json:"vertical_speed_mps"`
+	}{
+		raw:            raw(d),
+		AltitudeMeters: d.AltitudeMeters(),
+		VerticalSpeed:  d.VerticalSpeed(),
+	})
+}
+
+func (d *BarometerData) AltitudeMeters() float64 {
+	if d.Altitude&0x8000 != 0 {
+		//high bit IS set so value is in meters
+		return float64(d.Altitude & 0x7FFF)
+	}
+	//high bit IS NOT set so value is in decimeters
+	return (float64(d.Altitude) - 10000) / 10
+}
+
+// VerticalSpeed in m/s, positive is climbing
+func (d *BarometerData) VerticalSpeed() float64 {
+	return float64(d.Speed) / 100
+}
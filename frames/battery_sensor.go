@@ -3,7 +3,9 @@ package frames
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 const (
@@ -11,10 +13,20 @@ const (
 )
 
 type BatterySensorData struct {
-	Voltage   int16 // dv Big-Endian
-	Current   int16 // da Big Endian
-	Used      int32 //int24 mAh Little Endian
-	Remaining int8  //percent (0-100)
+	Voltage   int16 `json:"voltage"`   // dv Big-Endian
+	Current   int16 `json:"current"`   // da Big Endian
+	Used      int32 `json:"used"`      //int24 mAh Big Endian
+	Remaining int8  `json:"remaining"` //percent (0-100)
+}
+
+// NewBatterySensorData builds battery telemetry from volts, amps, used mAh and remaining percent
+func NewBatterySensorData(volts, amps float64, usedMah int32, remainingPercent int8) BatterySensorData {
+	return BatterySensorData{
+		Voltage:   int16(math.Round(volts * 10)),
+		Current:   int16(math.Round(amps * 10)),
+		Used:      usedMah,
+		Remaining: remainingPercent,
+	}
 }
 
 func UnmarshalBatterySensor(data []byte) (BatterySensorData, error) {
@@ -30,9 +42,9 @@ func UnmarshalBatterySensor(data []byte) (BatterySensorData, error) {
 	d.Voltage = int16(binary.BigEndian.Uint16(data[1:3]))
 	d.Current = int16(binary.BigEndian.Uint16(data[3:5]))
 
-	paddedInt24 := []byte{0} //add zero byte to front of 3 data bytes to pad out to 4 bytes for int32, little endian so 0 should go first
+	paddedInt24 := []byte{0} //add zero byte to front of 3 data bytes to pad out to 4 bytes for int32, big endian so 0 should go first
 	paddedInt24 = append(paddedInt24, data[5:8]...)
-	d.Used = int32(binary.LittleEndian.Uint32(paddedInt24))
+	d.Used = int32(binary.BigEndian.Uint32(paddedInt24))
 
 	d.Remaining = int8(data[8])
 
@@ -40,7 +52,62 @@ func UnmarshalBatterySensor(data []byte) (BatterySensorData, error) {
 	return d, nil
 }
 
+func (d *BatterySensorData) MarshalBatterySensor() []byte {
+	payload := make([]byte, BatterySensorFrameLength-2)
+	binary.BigEndian.PutUint16(payload[0:2], uint16(d.Voltage))
+	binary.BigEndian.PutUint16(payload[2:4], uint16(d.Current))
+
+	paddedInt24 := make([]byte, 4)
+	binary.BigEndian.PutUint32(paddedInt24, uint32(d.Used))
+	copy(payload[4:7], paddedInt24[1:]) //drop the padding byte to get back to int24
+
+	payload[7] = byte(d.Remaining)
+	return payload
+}
+
+func (d *BatterySensorData) Type() FrameType {
+	return FrameTypeBatterySensor
+}
+
+// MarshalPayload implements Frame
+func (d *BatterySensorData) MarshalPayload() []byte {
+	return d.MarshalBatterySensor()
+}
+
 func (d *BatterySensorData) String() string {
-	voltage := int(d.Voltage) * 10
-	return fmt.Sprintf("Voltage: %dV Current: %dda Used: %dmAh Remaining: %d%%", voltage, d.Current, d.Used, d.Remaining)
+	return fmt.Sprintf("Voltage: %.1fV Current: %.1fA Used: %dmAh Remaining: %d%%", d.Volts(), d.Amps(), d.UsedMah(), d.RemainingPercent())
+}
+
+func (d *BatterySensorData) Volts() float64 {
+	return float64(d.Voltage) / 10
+}
+
+func (d *BatterySensorData) Amps() float64 {
+	return float64(d.Current) / 10
+}
+
+func (d *BatterySensorData) UsedMah() int32 {
+	return d.Used
+}
+
+func (d *BatterySensorData) RemainingPercent() int8 {
+	return d.Remaining
+}
+
+// MarshalJSON includes the raw fields and their values in volts, amps, mAh and percent
+func (d BatterySensorData) MarshalJSON() ([]byte, error) {
+	type raw BatterySensorData
+	return json.Marshal(struct {
+		raw
+		Volts            float64 `json:"volts"`
+		Amps             float64 `json:"amps"`
+		UsedMah          int32   `json:"used_mah"`
+		RemainingPercent int8    `json:"remaining_percent"`
+	}{
+		raw:              raw(d),
+		Volts:            d.Volts(),
+		Amps:             d.Amps(),
+		UsedMah:          d.UsedMah(),
+		RemainingPercent: d.RemainingPercent(),
+	})
 }
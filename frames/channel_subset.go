@@ -0,0 +1,143 @@
+// https://github.com/crsf-wg/crsf/wiki/CRSF_FRAMETYPE_SUBSET_RC_CHANNELS_PACKED
+package frames
+
+import (
+	"fmt"
+)
+
+const (
+	MinChannelSubSetFrameLength = 1 + 2     //Config + Type + CRC
+	ChannelSubSetStartMask      = 0x1F      //bits 0-4 of the config byte
+	ChannelSubSetResolutionMask = 0x03      //bits 5-6 of the config byte
+	ChannelSubSetResolutionBit  = 5         //shift for the resolution bits
+	ChannelsResolutionBits      = uint8(11) //resolution of ChannelsData
+)
+
+// ChannelResolution is the number of bits per channel in a subset frame
+type ChannelResolution uint8
+
+const (
+	ChannelResolution10Bit ChannelResolution = iota
+	ChannelResolution11Bit
+	ChannelResolution12Bit
+	ChannelResolution13Bit
+)
+
+func (r ChannelResolution) Bits() uint8 {
+	return uint8(r) + 10
+}
+
+func (r ChannelResolution) String() string {
+	return fmt.Sprintf("%dbit", r.Bits())
+}
+
+type ChannelSubSetData struct {
+	StartChannel uint8             `json:"start_channel"` //index of the first channel, 0 is channel 1
+	Resolution   ChannelResolution `json:"resolution"`
+	Channels     []uint16          `json:"channels"` //values at Resolution
+}
+
+func UnmarshalChannelSubSet(data []byte) (ChannelSubSetData, error) {
+	d := ChannelSubSetData{}
+	if len(data) < MinChannelSubSetFrameLength {
+		return d, ErrFrameLength
+	}
+	if !ValidateFrame(data) {
+		return d, ErrInvalidCRC8
+	}
+
+	config := data[1]
+	d.StartChannel = config & ChannelSubSetStartMask
+	d.Resolution = ChannelResolution((config >> ChannelSubSetResolutionBit) & ChannelSubSetResolutionMask)
+
+	packed := data[2 : len(data)-1]
+	bits := int(d.Resolution.Bits())
+	count := len(packed) * 8 / bits
+	mask := uint32(1)<<bits - 1
+
+	d.Channels = make([]uint16, count)
+	bitBuffer := uint32(0)
+	bitsInBuffer := 0
+	byteIdx := 0
+	for i := range d.Channels {
+		for bitsInBuffer < bits {
+			bitBuffer |= uint32(packed[byteIdx]) << bitsInBuffer
+			bitsInBuffer += 8
+			byteIdx++
+		}
+		d.Channels[i] = uint16(bitBuffer & mask)
+		bitBuffer >>= bits
+		bitsInBuffer -= bits
+	}
+
+	return d, nil
+}
+
+// MarshalChannelSubSet returns nil when there are no channels, a subset frame without channels is not sent
+func (d *ChannelSubSetData) MarshalChannelSubSet() []byte {
+	if len(d.Channels) == 0 {
+		return nil
+	}
+
+	bits := int(d.Resolution.Bits())
+	payload := make([]byte, 1, 1+(len(d.Channels)*bits+7)/8)
+	payload[0] = (d.StartChannel & ChannelSubSetStartMask) | (byte(d.Resolution)&ChannelSubSetResolutionMask)<<ChannelSubSetResolutionBit
+
+	mask := uint32(1)<<bits - 1
+	bitBuffer := uint32(0)
+	bitsInBuffer := 0
+	for _, channel := range d.Channels {
+		bitBuffer |= (uint32(channel) & mask) << bitsInBuffer
+		bitsInBuffer += bits
+		for bitsInBuffer >= 8 {
+			payload = append(payload, byte(bitBuffer))
+			bitBuffer >>= 8
+			bitsInBuffer -= 8
+		}
+	}
+	if bitsInBuffer > 0 {
+		payload = append(payload, byte(bitBuffer))
+	}
+
+	return payload
+}
+
+func (d *ChannelSubSetData) Type() FrameType {
+	return FrameTypeChannelSubSet
+}
+
+// MarshalPayload implements Frame
+func (d *ChannelSubSetData) MarshalPayload() []byte {
+	return d.MarshalChannelSubSet()
+}
+
+// NewChannelSubSet takes count channels starting at startChannel from channels and scales them to resolution
+func NewChannelSubSet(channels ChannelsData, startChannel, count uint8, resolution ChannelResolution) ChannelSubSetData {
+	d := ChannelSubSetData{
+		StartChannel: startChannel,
+		Resolution:   resolution,
+		Channels:     make([]uint16, 0, count),
+	}
+	for i := int(startChannel); i < int(startChannel)+int(count) && i < len(channels.Channels); i++ {
+		d.Channels = append(d.Channels, scaleChannel(channels.Channels[i], ChannelsResolutionBits, resolution.Bits()))
+	}
+	return d
+}
+
+// ApplySubSet merges the subset into the 11 bit channels, growing them to MaxChannels if needed
+func (d *ChannelsData) ApplySubSet(subSet ChannelSubSetData) {
+	for i, value := range subSet.Channels {
+		d.SetTicks(int(subSet.StartChannel)+i, scaleChannel(value, subSet.Resolution.Bits(), ChannelsResolutionBits))
+	}
+}
+
+func (d *ChannelSubSetData) String() string {
+	return fmt.Sprintf("StartChannel: %d Resolution: %s Channels: %v", d.StartChannel+1, d.Resolution.String(), d.Channels)
+}
+
+func scaleChannel(value uint16, fromBits, toBits uint8) uint16 {
+	if fromBits > toBits {
+		return value >> (fromBits - toBits)
+	}
+	return value << (toBits - fromBits)
+}
@@ -0,0 +1,135 @@
+package frames
+
+import (
+	"math"
+)
+
+const (
+	ChannelsMinMicroseconds = 988
+	ChannelsMidMicroseconds = 1500
+	ChannelsMaxMicroseconds = 2012
+
+	ticksPerMicrosecond = 8.0 / 5.0 //(ChannelsMax-ChannelsMin) / (ChannelsMaxMicroseconds-ChannelsMinMicroseconds)
+)
+
+// TicksToMicroseconds converts an 11 bit channel value to a PWM pulse width (172 = 988us, 992 = 1500us, 1811 = 2012us)
+func TicksToMicroseconds(ticks uint16) float64 {
+	return ChannelsMidMicroseconds + (float64(ticks)-ChannelsMid)/ticksPerMicrosecond
+}
+
+// MicrosecondsToTicks converts a PWM pulse width to an 11 bit channel value
+func MicrosecondsToTicks(us float64) uint16 {
+	return clampTicks(ChannelsMid + (us-ChannelsMidMicroseconds)*ticksPerMicrosecond)
+}
+
+// TicksToNormalized converts a channel value to -1 (ChannelsMin) through 0 (ChannelsMid) to 1 (ChannelsMax)
+func TicksToNormalized(ticks uint16) float64 {
+	if ticks < ChannelsMid {
+		return max(-1, (float64(ticks)-ChannelsMid)/(ChannelsMid-ChannelsMin))
+	}
+	return min(1, (float64(ticks)-ChannelsMid)/(ChannelsMax-ChannelsMid))
+}
+
+// NormalizedToTicks converts -1 to 1 to a channel value, values outside the range are clamped
+func NormalizedToTicks(value float64) uint16 {
+	value = min(max(value, -1), 1)
+	if value < 0 {
+		return clampTicks(ChannelsMid + value*(ChannelsMid-ChannelsMin))
+	}
+	return clampTicks(ChannelsMid + value*(ChannelsMax-ChannelsMid))
+}
+
+// TicksToUnit converts a channel value to 0 (ChannelsMin) to 1 (ChannelsMax), useful for throttle
+func TicksToUnit(ticks uint16) float64 {
+	return min(max((float64(ticks)-ChannelsMin)/(ChannelsMax-ChannelsMin), 0), 1)
+}
+
+// UnitToTicks converts 0 to 1 to a channel value, values outside the range are clamped
+func UnitToTicks(value float64) uint16 {
+	value = min(max(value, 0), 1)
+	return clampTicks(ChannelsMin + value*(ChannelsMax-ChannelsMin))
+}
+
+// TicksToSwitchPosition splits ChannelsMin to ChannelsMax into positions equal ranges and returns the one ticks is in, 0 is the lowest
+func TicksToSwitchPosition(ticks uint16, positions int) int {
+	if positions < 2 || ticks <= ChannelsMin {
+		return 0
+	}
+	if ticks >= ChannelsMax {
+		return positions - 1
+	}
+	return (int(ticks) - ChannelsMin) * positions / (ChannelsMax - ChannelsMin + 1)
+}
+
+// SwitchPositionToTicks returns the channel value for a switch position, positions are spread evenly from ChannelsMin to ChannelsMax
+func SwitchPositionToTicks(position, positions int) uint16 {
+	if positions < 2 || position <= 0 {
+		return ChannelsMin
+	}
+	if position >= positions-1 {
+		return ChannelsMax
+	}
+	return uint16(ChannelsMin + (ChannelsMax-ChannelsMin)*position/(positions-1))
+}
+
+// Microseconds returns the channel at index ch (0 is channel 1) as a PWM pulse width
+func (d *ChannelsData) Microseconds(ch int) float64 {
+	return TicksToMicroseconds(d.Ticks(ch))
+}
+
+func (d *ChannelsData) SetMicroseconds(ch int, us float64) {
+	d.SetTicks(ch, MicrosecondsToTicks(us))
+}
+
+// Normalized returns the channel at index ch (0 is channel 1) as -1 to 1
+func (d *ChannelsData) Normalized(ch int) float64 {
+	return TicksToNormalized(d.Ticks(ch))
+}
+
+func (d *ChannelsData) SetNormalized(ch int, value float64) {
+	d.SetTicks(ch, NormalizedToTicks(value))
+}
+
+// Unit returns the channel at index ch (0 is channel 1) as 0 to 1
+func (d *ChannelsData) Unit(ch int) float64 {
+	return TicksToUnit(d.Ticks(ch))
+}
+
+func (d *ChannelsData) SetUnit(ch int, value float64) {
+	d.SetTicks(ch, UnitToTicks(value))
+}
+
+// SwitchPosition returns the position of a 2, 3 or 6 (or any count) position switch on the channel at index ch (0 is channel 1)
+func (d *ChannelsData) SwitchPosition(ch int, positions int) int {
+	return TicksToSwitchPosition(d.Ticks(ch), positions)
+}
+
+func (d *ChannelsData) SetSwitchPosition(ch int, position, positions int) {
+	d.SetTicks(ch, SwitchPositionToTicks(position, positions))
+}
+
+// Ticks returns the 11 bit value of the channel at index ch (0 is channel 1), 0 for channels that have not been set
+func (d *ChannelsData) Ticks(ch int) uint16 {
+	if ch < 0 || ch >= len(d.Channels) {
+		return 0
+	}
+	return d.Channels[ch]
+}
+
+// SetTicks sets the 11 bit value of the channel at index ch (0 is channel 1).
+// Channels are grown to MaxChannels if needed, channels past MaxChannels are ignored.
+func (d *ChannelsData) SetTicks(ch int, ticks uint16) {
+	if ch < 0 || ch >= MaxChannels {
+		return
+	}
+	if len(d.Channels) < MaxChannels {
+		channels := make([]uint16, MaxChannels)
+		copy(channels, d.Channels)
+		d.Channels = channels
+	}
+	d.Channels[ch] = ticks
+}
+
+func clampTicks(ticks float64) uint16 {
+	return uint16(min(max(math.Round(ticks), 0), float64(ChannelsMask)))
+}
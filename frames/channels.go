@@ -2,6 +2,7 @@
 package frames
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 )
@@ -18,7 +19,7 @@ const (
 )
 
 type ChannelsData struct {
-	Channels []uint16
+	Channels []uint16 `json:"channels"`
 }
 
 func UnmarshalChannels(data []byte) (ChannelsData, error) {
@@ -55,42 +56,36 @@ func UnmarshalChannels(data []byte) (ChannelsData, error) {
 	return d, nil
 }
 
-// TODO: check if this is correct
 func (d *ChannelsData) MarshalChannels() []byte {
-	const PAYLOAD_LEN = 22
-	payload := make([]byte, PAYLOAD_LEN)
-
 	ch := d.Channels
 	if len(ch) < MaxChannels {
-		return nil // or handle error
+		return nil
 	}
 
-	payload[0] = byte(ch[0] & 0xFF)
-	payload[1] = byte((ch[0] >> 8) | ((ch[1] & 0x07) << 3))
-	payload[2] = byte((ch[1] >> 3) | ((ch[2] & 0x3F) << 5))
-	payload[3] = byte((ch[2] >> 6) | ((ch[3] & 0x01FF) << 2))
-	payload[4] = byte((ch[3] >> 6) | ((ch[4] & 0x0F) << 7))
-	payload[5] = byte((ch[4] >> 4) | ((ch[5] & 0x7F) << 4))
-	payload[6] = byte((ch[5] >> 7) | ((ch[6] & 0x3F) << 1))
-	payload[7] = byte((ch[6] >> 6) | ((ch[7] & 0xFF) << 3))
-	payload[8] = byte((ch[7] >> 5) | ((ch[8] & 0x1F) << 6))
-	payload[9] = byte((ch[8] >> 2))
-	payload[10] = byte((ch[8] >> 10) | ((ch[9] & 0xFF) << 1))
-	payload[11] = byte((ch[9] >> 7) | ((ch[10] & 0x7F) << 4))
-	payload[12] = byte((ch[10] >> 4) | ((ch[11] & 0x0F) << 7))
-	payload[13] = byte((ch[11] >> 1))
-	payload[14] = byte((ch[11] >> 9) | ((ch[12] & 0x3F) << 2))
-	payload[15] = byte((ch[12] >> 6) | ((ch[13] & 0x1F) << 5))
-	payload[16] = byte((ch[13] >> 3))
-	payload[17] = byte((ch[14] & 0xFF))
-	payload[18] = byte((ch[14] >> 8) | ((ch[15] & 0x07) << 3))
-	payload[19] = byte((ch[15] >> 3))
-	payload[20] = 0
-	payload[21] = 0
-
+	//16 channels of 11 bits packed least significant bit first, the reverse of UnmarshalChannels
+	payload := make([]byte, ChannelsFrameLength-2)
+	bit := 0
+	for _, value := range ch[:MaxChannels] {
+		value &= ChannelsMask
+		for i := 0; i < 11; i++ {
+			if value&(1<<i) != 0 {
+				payload[bit/8] |= 1 << (bit % 8)
+			}
+			bit++
+		}
+	}
 	return payload
 }
 
+func (d *ChannelsData) Type() FrameType {
+	return FrameTypeChannels
+}
+
+// MarshalPayload implements Frame
+func (d *ChannelsData) MarshalPayload() []byte {
+	return d.MarshalChannels()
+}
+
 func (d *ChannelsData) String() string {
 	builtString := ""
 	for i := range d.Channels {
@@ -103,3 +98,19 @@ func (d *ChannelsData) String() string {
 
 	return builtString
 }
+
+// MarshalJSON includes the raw channels and their values in microseconds
+func (d ChannelsData) MarshalJSON() ([]byte, error) {
+	type raw ChannelsData
+	microseconds := make([]float64, len(d.Channels))
+	for i := range d.Channels {
+		microseconds[i] = TicksToMicroseconds(d.Channels[i])
+	}
+	return json.Marshal(struct {
+		raw
+		Microseconds []float64 `json:"channels_us"`
+	}{
+		raw:          raw(d),
+		Microseconds: microseconds,
+	})
+}
@@ -0,0 +1,72 @@
+package frames
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+	"testing"
+)
+
+func channelsOf(first []uint16, rest uint16) []uint16 {
+	channels := slices.Repeat([]uint16{rest}, MaxChannels)
+	copy(channels, first)
+	return channels
+}
+
+func TestMarshalChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels []uint16
+		payload  string
+	}{
+		{
+			name:     "all centered",
+			channels: channelsOf(nil, ChannelsMid),
+			payload:  "e0031ff8c0073ef0810f7ce0031ff8c0073ef0810f7c",
+		},
+		{
+			name:     "limits",
+			channels: channelsOf([]uint16{ChannelsMin, ChannelsMax, ChannelsMid, 0, ChannelsMask}, ChannelsMid),
+			payload:  "ac9838f800f07ff0810f7ce0031ff8c0073ef0810f7c",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := ChannelsData{Channels: test.channels}
+			payload := d.MarshalChannels()
+			if got := hex.EncodeToString(payload); got != test.payload {
+				t.Fatalf("payload %s want %s", got, test.payload)
+			}
+
+			//through the encoder and decoder as a frame on the wire
+			var stream bytes.Buffer
+			err := NewEncoder(&stream).Encode(AddressTypeTransmitter, &d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := NewDecoder(&stream).Decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if raw.Address != AddressTypeTransmitter || raw.Type() != FrameTypeChannels {
+				t.Fatalf("decoded %s %s", raw.Address, raw.Type())
+			}
+
+			decoded, err := UnmarshalChannels(raw.Frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(decoded.Channels, test.channels) {
+				t.Fatalf("channels %v want %v", decoded.Channels, test.channels)
+			}
+		})
+	}
+}
+
+func TestMarshalChannelsTooFew(t *testing.T) {
+	d := ChannelsData{Channels: make([]uint16, MaxChannels-1)}
+	if payload := d.MarshalChannels(); payload != nil {
+		t.Fatalf("expected no payload for %d channels, got %x", len(d.Channels), payload)
+	}
+}
@@ -0,0 +1,410 @@
+// https://github.com/crsf-wg/crsf/wiki/CRSF_FRAMETYPE_COMMAND
+package frames
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	CommandHeaderLength = 2 //Realm + Command
+	CommandMinLength    = CommandHeaderLength + 1
+
+	CommandAckMinLength = 3 //Realm + Command + Action
+)
+
+// Commands per realm
+const (
+	CommandFCForceDisarm  = 0x01
+	CommandFCScaleChannel = 0x02
+
+	CommandBluetoothReset  = 0x01
+	CommandBluetoothEnable = 0x02
+	CommandBluetoothEcho   = 0x64
+
+	CommandOSDSendButtons = 0x01
+
+	CommandVTXChangeChannel      = 0x01
+	CommandVTXSetFrequency       = 0x02
+	CommandVTXChangePower        = 0x03
+	CommandVTXPitModeOnPowerUp   = 0x04
+	CommandVTXPowerUpFromPitMode = 0x05
+	CommandVTXSetDynamicPower    = 0x06
+	CommandVTXSetPower           = 0x08
+
+	CommandLEDSetDefault    = 0x01
+	CommandLEDOverrideColor = 0x02
+	CommandLEDOverridePulse = 0x03
+	CommandLEDOverrideBlink = 0x04
+	CommandLEDOverrideShift = 0x05
+
+	CommandGeneralSpeedProposal = 0x70
+	CommandGeneralSpeedResponse = 0x71
+
+	CommandCrossfireBind       = 0x01 //put the receiver in bind mode
+	CommandCrossfireCancelBind = 0x02
+	CommandCrossfireSetModel   = 0x05
+	CommandCrossfireQueryModel = 0x06
+	CommandCrossfireModelReply = 0x07
+
+	CommandACK = 0x01 //command byte of an ACK, the acknowledged command is in the payload
+)
+
+// OSD buttons for CommandOSDSendButtons
+const (
+	OSDButtonEnter = 0x80
+	OSDButtonUp    = 0x40
+	OSDButtonDown  = 0x20
+	OSDButtonLeft  = 0x10
+	OSDButtonRight = 0x08
+)
+
+var ErrInvalidCommandCRC8 = errors.New("command failed inner crc8 validation")
+
+// CommandData is the body of a COMMAND frame, the inner crc is added and checked by the frame functions
+type CommandData struct {
+	Realm   CommandRealm `json:"realm"`
+	Command uint8        `json:"command"`
+	Payload []byte       `json:"payload"`
+}
+
+// NewCommandFrame builds the extended frame for d, including the inner crc over type, addresses and command
+func NewCommandFrame(destination, origin AddressType, d CommandData) ExtendedFrame {
+	frame := ExtendedFrame{
+		Type:        FrameTypeCommand,
+		Destination: destination,
+		Origin:      origin,
+	}
+	payload := make([]byte, 0, CommandHeaderLength+len(d.Payload)+1)
+	payload = append(payload, byte(d.Realm), d.Command)
+	payload = append(payload, d.Payload...)
+	frame.Payload = append(payload, commandCrc(frame, payload))
+	return frame
+}
+
+// UnmarshalCommandFrame decodes the payload of an extended COMMAND frame and validates its inner crc
+func UnmarshalCommandFrame(frame ExtendedFrame) (CommandData, error) {
+	d := CommandData{}
+	if frame.Type != FrameTypeCommand {
+		return d, fmt.Errorf("frame type %s is not a command", frame.Type.String())
+	}
+	if len(frame.Payload) < CommandMinLength {
+		return d, ErrFrameLength
+	}
+
+	body := frame.Payload[:len(frame.Payload)-1]
+	if commandCrc(frame, body) != frame.Payload[len(frame.Payload)-1] {
+		return d, ErrInvalidCommandCRC8
+	}
+
+	d.Realm = CommandRealm(body[0])
+	d.Command = body[1]
+	d.Payload = body[CommandHeaderLength:]
+	return d, nil
+}
+
+func commandCrc(frame ExtendedFrame, body []byte) uint8 {
+	crc := uint8(0)
+	for _, value := range []byte{byte(frame.Type), byte(frame.Destination), byte(frame.Origin)} {
+		crc = Crc8BA(crc, value)
+	}
+	for _, value := range body {
+		crc = Crc8BA(crc, value)
+	}
+	return crc
+}
+
+// IsAck reports if d acknowledges the command realm and command
+func (d *CommandData) IsAck(realm CommandRealm, command uint8) bool {
+	if d.Realm != CommandRealmACK || len(d.Payload) < 2 {
+		return false
+	}
+	return CommandRealm(d.Payload[0]) == realm && d.Payload[1] == command
+}
+
+func (d *CommandData) Type() FrameType {
+	return FrameTypeCommand
+}
+
+// MarshalExtendedPayload implements ExtendedData, the inner crc covers the destination and origin
+func (d *CommandData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return NewCommandFrame(destination, origin, *d).Payload
+}
+
+func (d *CommandData) String() string {
+	return fmt.Sprintf("Realm: %s Command: 0x%02X Payload: %v", d.Realm.String(), d.Command, d.Payload)
+}
+
+// CommandAckData answers a command. Action is false if the command is not supported.
+type CommandAckData struct {
+	Realm       CommandRealm `json:"realm"`
+	Command     uint8        `json:"command"`
+	Action      bool         `json:"action"`
+	Information string       `json:"information,omitempty"`
+}
+
+func NewCommandAck(d CommandAckData) CommandData {
+	payload := []byte{byte(d.Realm), d.Command, boolByte(d.Action)}
+	if d.Information != "" {
+		payload = append(payload, d.Information...)
+		payload = append(payload, 0)
+	}
+	return CommandData{Realm: CommandRealmACK, Command: CommandACK, Payload: payload}
+}
+
+func UnmarshalCommandAck(d CommandData) (CommandAckData, error) {
+	a := CommandAckData{}
+	if d.Realm != CommandRealmACK {
+		return a, fmt.Errorf("command realm %s is not an ack", d.Realm.String())
+	}
+	if len(d.Payload) < CommandAckMinLength {
+		return a, ErrFrameLength
+	}
+	a.Realm = CommandRealm(d.Payload[0])
+	a.Command = d.Payload[1]
+	a.Action = d.Payload[2] != 0
+	info := d.Payload[CommandAckMinLength:]
+	if i := bytes.IndexByte(info, 0); i >= 0 {
+		info = info[:i]
+	}
+	a.Information = string(info)
+	return a, nil
+}
+
+func (d *CommandAckData) Type() FrameType {
+	return FrameTypeCommand
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *CommandAckData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	command := NewCommandAck(*d)
+	return command.MarshalExtendedPayload(destination, origin)
+}
+
+func (d *CommandAckData) String() string {
+	return fmt.Sprintf("Realm: %s Command: 0x%02X Action: %t Information: %s", d.Realm.String(), d.Command, d.Action, d.Information)
+}
+
+// SpeedProposalData asks a device to switch the baud rate of a port
+type SpeedProposalData struct {
+	Port     uint8  `json:"port"`
+	BaudRate uint32 `json:"baud_rate"`
+}
+
+func NewSpeedProposalCommand(d SpeedProposalData) CommandData {
+	payload := binary.BigEndian.AppendUint32([]byte{d.Port}, d.BaudRate)
+	return CommandData{Realm: CommandRealmGeneral, Command: CommandGeneralSpeedProposal, Payload: payload}
+}
+
+func (d *SpeedProposalData) Type() FrameType {
+	return FrameTypeCommand
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *SpeedProposalData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	command := NewSpeedProposalCommand(*d)
+	return command.MarshalExtendedPayload(destination, origin)
+}
+
+func UnmarshalSpeedProposal(d CommandData) (SpeedProposalData, error) {
+	s := SpeedProposalData{}
+	if d.Realm != CommandRealmGeneral || d.Command != CommandGeneralSpeedProposal {
+		return s, fmt.Errorf("command %s is not a speed proposal", d.String())
+	}
+	if len(d.Payload) < 5 {
+		return s, ErrFrameLength
+	}
+	s.Port = d.Payload[0]
+	s.BaudRate = binary.BigEndian.Uint32(d.Payload[1:5])
+	return s, nil
+}
+
+// SpeedResponseData accepts or rejects a speed proposal
+type SpeedResponseData struct {
+	Port     uint8 `json:"port"`
+	Accepted bool  `json:"accepted"`
+}
+
+func NewSpeedResponseCommand(d SpeedResponseData) CommandData {
+	return CommandData{Realm: CommandRealmGeneral, Command: CommandGeneralSpeedResponse, Payload: []byte{d.Port, boolByte(d.Accepted)}}
+}
+
+func (d *SpeedResponseData) Type() FrameType {
+	return FrameTypeCommand
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *SpeedResponseData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	command := NewSpeedResponseCommand(*d)
+	return command.MarshalExtendedPayload(destination, origin)
+}
+
+func UnmarshalSpeedResponse(d CommandData) (SpeedResponseData, error) {
+	s := SpeedResponseData{}
+	if d.Realm != CommandRealmGeneral || d.Command != CommandGeneralSpeedResponse {
+		return s, fmt.Errorf("command %s is not a speed response", d.String())
+	}
+	if len(d.Payload) < 2 {
+		return s, ErrFrameLength
+	}
+	s.Port = d.Payload[0]
+	s.Accepted = d.Payload[1] != 0
+	return s, nil
+}
+
+func NewForceDisarmCommand() CommandData {
+	return CommandData{Realm: CommandRealmFC, Command: CommandFCForceDisarm}
+}
+
+// NewScaleChannelCommand carries the flight controller specific scale parameters as is
+func NewScaleChannelCommand(payload []byte) CommandData {
+	return CommandData{Realm: CommandRealmFC, Command: CommandFCScaleChannel, Payload: payload}
+}
+
+func NewBluetoothResetCommand() CommandData {
+	return CommandData{Realm: CommandRealmBluetooth, Command: CommandBluetoothReset}
+}
+
+func NewBluetoothEnableCommand(enable bool) CommandData {
+	return CommandData{Realm: CommandRealmBluetooth, Command: CommandBluetoothEnable, Payload: []byte{boolByte(enable)}}
+}
+
+func NewBluetoothEchoCommand() CommandData {
+	return CommandData{Realm: CommandRealmBluetooth, Command: CommandBluetoothEcho}
+}
+
+// NewOSDButtonsCommand presses the OSDButton bits set in buttons
+func NewOSDButtonsCommand(buttons uint8) CommandData {
+	return CommandData{Realm: CommandRealmOSD, Command: CommandOSDSendButtons, Payload: []byte{buttons}}
+}
+
+func NewVTXChannelCommand(channel uint8) CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXChangeChannel, Payload: []byte{channel}}
+}
+
+func NewVTXFrequencyCommand(mhz uint16) CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXSetFrequency, Payload: binary.BigEndian.AppendUint16(nil, mhz)}
+}
+
+func NewVTXPitModeOnPowerUpCommand(pitMode uint8) CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXPitModeOnPowerUp, Payload: []byte{pitMode}}
+}
+
+func NewVTXPowerUpFromPitModeCommand() CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXPowerUpFromPitMode}
+}
+
+// NewVTXDynamicPowerCommand sets the power used while disarmed in dBm
+func NewVTXDynamicPowerCommand(dBm uint8) CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXSetDynamicPower, Payload: []byte{dBm}}
+}
+
+func NewVTXPowerCommand(dBm uint8) CommandData {
+	return CommandData{Realm: CommandRealmVTX, Command: CommandVTXSetPower, Payload: []byte{dBm}}
+}
+
+// VTXFrequency decodes a CommandVTXSetFrequency command
+func (d *CommandData) VTXFrequency() (uint16, error) {
+	if d.Realm != CommandRealmVTX || d.Command != CommandVTXSetFrequency {
+		return 0, fmt.Errorf("command %s is not a vtx frequency", d.String())
+	}
+	if len(d.Payload) < 2 {
+		return 0, ErrFrameLength
+	}
+	return binary.BigEndian.Uint16(d.Payload), nil
+}
+
+// LEDColor is packed as 9 bits hue (0-359), 7 bits saturation and 8 bits value
+type LEDColor struct {
+	Hue        uint16
+	Saturation uint8
+	Value      uint8
+}
+
+func (c LEDColor) marshal() []byte {
+	packed := uint32(c.Hue&0x1FF)<<15 | uint32(c.Saturation&0x7F)<<8 | uint32(c.Value)
+	return []byte{byte(packed >> 16), byte(packed >> 8), byte(packed)}
+}
+
+func UnmarshalLEDColor(data []byte) (LEDColor, error) {
+	if len(data) < 3 {
+		return LEDColor{}, ErrFrameLength
+	}
+	packed := uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+	return LEDColor{
+		Hue:        uint16(packed>>15) & 0x1FF,
+		Saturation: uint8(packed>>8) & 0x7F,
+		Value:      uint8(packed),
+	}, nil
+}
+
+func NewLEDDefaultCommand() CommandData {
+	return CommandData{Realm: CommandRealmLED, Command: CommandLEDSetDefault}
+}
+
+func NewLEDColorCommand(color LEDColor) CommandData {
+	return CommandData{Realm: CommandRealmLED, Command: CommandLEDOverrideColor, Payload: color.marshal()}
+}
+
+// NewLEDPulseCommand fades from start to stop over durationMs
+func NewLEDPulseCommand(durationMs uint16, start, stop LEDColor) CommandData {
+	payload := binary.BigEndian.AppendUint16(nil, durationMs)
+	payload = append(payload, start.marshal()...)
+	payload = append(payload, stop.marshal()...)
+	return CommandData{Realm: CommandRealmLED, Command: CommandLEDOverridePulse, Payload: payload}
+}
+
+// NewLEDBlinkCommand switches between start and stop every intervalMs
+func NewLEDBlinkCommand(intervalMs uint16, start, stop LEDColor) CommandData {
+	payload := binary.BigEndian.AppendUint16(nil, intervalMs)
+	payload = append(payload, start.marshal()...)
+	payload = append(payload, stop.marshal()...)
+	return CommandData{Realm: CommandRealmLED, Command: CommandLEDOverrideBlink, Payload: payload}
+}
+
+func NewLEDShiftCommand(intervalMs uint16, color LEDColor) CommandData {
+	payload := binary.BigEndian.AppendUint16(nil, intervalMs)
+	payload = append(payload, color.marshal()...)
+	return CommandData{Realm: CommandRealmLED, Command: CommandLEDOverrideShift, Payload: payload}
+}
+
+// NewBindCommand puts a receiver in bind mode
+func NewBindCommand() CommandData {
+	return CommandData{Realm: CommandRealmCrossfire, Command: CommandCrossfireBind}
+}
+
+func NewCancelBindCommand() CommandData {
+	return CommandData{Realm: CommandRealmCrossfire, Command: CommandCrossfireCancelBind}
+}
+
+func NewSetModelCommand(model uint8) CommandData {
+	return CommandData{Realm: CommandRealmCrossfire, Command: CommandCrossfireSetModel, Payload: []byte{model}}
+}
+
+func NewQueryModelCommand() CommandData {
+	return CommandData{Realm: CommandRealmCrossfire, Command: CommandCrossfireQueryModel}
+}
+
+func NewModelReplyCommand(model uint8) CommandData {
+	return CommandData{Realm: CommandRealmCrossfire, Command: CommandCrossfireModelReply, Payload: []byte{model}}
+}
+
+// Model decodes CommandCrossfireSetModel and CommandCrossfireModelReply commands
+func (d *CommandData) Model() (uint8, error) {
+	if d.Realm != CommandRealmCrossfire || (d.Command != CommandCrossfireSetModel && d.Command != CommandCrossfireModelReply) {
+		return 0, fmt.Errorf("command %s has no model", d.String())
+	}
+	if len(d.Payload) < 1 {
+		return 0, ErrFrameLength
+	}
+	return d.Payload[0], nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
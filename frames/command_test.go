@@ -0,0 +1,147 @@
+package frames
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// bindFrame is the ELRS bind command sent to a receiver, EC 07 32 EC C8 10 01 9E E8
+const bindFrame = "ec0732ecc810019ee8"
+
+func decodeCommand(t *testing.T, wire []byte) CommandData {
+	t.Helper()
+	raw, err := NewDecoder(bytes.NewReader(wire)).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	extended, err := UnmarshalExtendedFrame(raw.Frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	command, err := UnmarshalCommandFrame(extended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return command
+}
+
+func TestCommandFrameKnown(t *testing.T) {
+	bind := NewBindCommand()
+	frame := NewCommandFrame(AddressTypeReceiver, AddressTypeFlightController, bind)
+	wire, err := AppendFrame(nil, AddressTypeReceiver, frame.Type, frame.MarshalExtendedFrame())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(wire); got != bindFrame {
+		t.Fatalf("bind frame %s want %s", got, bindFrame)
+	}
+
+	known, _ := hex.DecodeString(bindFrame)
+	command := decodeCommand(t, known)
+	if command.Realm != CommandRealmCrossfire || command.Command != CommandCrossfireBind || len(command.Payload) != 0 {
+		t.Fatalf("decoded %s", command.String())
+	}
+}
+
+func TestCommandFrameInnerCrc(t *testing.T) {
+	frame := NewCommandFrame(AddressTypeReceiver, AddressTypeFlightController, NewBindCommand())
+	frame.Payload[len(frame.Payload)-1] ^= 0xFF
+	_, err := UnmarshalCommandFrame(frame)
+	if !errors.Is(err, ErrInvalidCommandCRC8) {
+		t.Fatalf("expected ErrInvalidCommandCRC8, got %v", err)
+	}
+
+	//the inner crc covers the addresses, so the same body sent elsewhere fails
+	frame = NewCommandFrame(AddressTypeReceiver, AddressTypeFlightController, NewBindCommand())
+	frame.Destination = AddressTypeTransmitter
+	_, err = UnmarshalCommandFrame(frame)
+	if !errors.Is(err, ErrInvalidCommandCRC8) {
+		t.Fatalf("expected ErrInvalidCommandCRC8 for another destination, got %v", err)
+	}
+}
+
+func TestCommandRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		command CommandData
+	}{
+		{"force disarm", NewForceDisarmCommand()},
+		{"osd buttons", NewOSDButtonsCommand(OSDButtonEnter | OSDButtonLeft)},
+		{"vtx frequency", NewVTXFrequencyCommand(5800)},
+		{"led color", NewLEDColorCommand(LEDColor{Hue: 359, Saturation: 127, Value: 255})},
+		{"led pulse", NewLEDPulseCommand(500, LEDColor{Hue: 0}, LEDColor{Hue: 120, Saturation: 64, Value: 10})},
+		{"set model", NewSetModelCommand(7)},
+		{"speed proposal", NewSpeedProposalCommand(SpeedProposalData{Port: 0, BaudRate: 921600})},
+		{"ack", NewCommandAck(CommandAckData{Realm: CommandRealmVTX, Command: CommandVTXSetPower, Action: true, Information: "ok"})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			frame := NewAddressedFrame(AddressTypeFlightController, AddressTypeRadioTransmitter, &test.command)
+			wire, err := AppendFrame(nil, AddressTypeFlightController, frame.Type(), frame.MarshalPayload())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			command := decodeCommand(t, wire)
+			if command.Realm != test.command.Realm || command.Command != test.command.Command || !slices.Equal(command.Payload, test.command.Payload) {
+				t.Fatalf("decoded %s want %s", command.String(), test.command.String())
+			}
+		})
+	}
+}
+
+func TestCommandAck(t *testing.T) {
+	tests := []CommandAckData{
+		{Realm: CommandRealmVTX, Command: CommandVTXSetPower, Action: true},
+		{Realm: CommandRealmGeneral, Command: CommandGeneralSpeedProposal, Action: false, Information: "unsupported"},
+	}
+
+	for _, want := range tests {
+		command := NewCommandAck(want)
+		if !command.IsAck(want.Realm, want.Command) {
+			t.Fatalf("%s is not an ack of %s 0x%02X", command.String(), want.Realm, want.Command)
+		}
+
+		ack, err := UnmarshalCommandAck(command)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ack != want {
+			t.Fatalf("ack %s want %s", ack.String(), want.String())
+		}
+	}
+
+	_, err := UnmarshalCommandAck(NewBindCommand())
+	if err == nil {
+		t.Fatal("expected an error decoding a command that is not an ack")
+	}
+}
+
+func TestLEDColor(t *testing.T) {
+	tests := []struct {
+		color LEDColor
+		wire  string
+	}{
+		{LEDColor{Hue: 0, Saturation: 0, Value: 0}, "000000"},
+		{LEDColor{Hue: 120, Saturation: 64, Value: 10}, "3c400a"},
+		{LEDColor{Hue: 359, Saturation: 127, Value: 255}, "b3ffff"},
+	}
+
+	for _, test := range tests {
+		wire := test.color.marshal()
+		if got := hex.EncodeToString(wire); got != test.wire {
+			t.Fatalf("%+v packed as %s want %s", test.color, got, test.wire)
+		}
+		color, err := UnmarshalLEDColor(wire)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if color != test.color {
+			t.Fatalf("unpacked %+v want %+v", color, test.color)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package frames
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	MaxFrameLength  = 64 // sync/address + length + up to 62 bytes of type, payload and crc
+	MinFrameBody    = 2  // type + crc
+	MaxFrameBody    = MaxFrameLength - 2
+	decoderRingSize = 1024 // power of two so positions can be masked
+	decoderRingMask = decoderRingSize - 1
+
+	maxConsecutiveEmptyReads = 100
+)
+
+// RawFrame is a frame as it was read, Frame points into the decoder buffer and is only valid until the next Decode
+type RawFrame struct {
+	Address AddressType
+	Frame   []byte // [type][payload][crc]
+}
+
+func (f RawFrame) Type() FrameType {
+	return FrameType(f.Frame[0])
+}
+
+// Payload is the frame without its type and crc, for extended frames it starts with the destination and origin
+func (f RawFrame) Payload() []byte {
+	return f.Frame[1 : len(f.Frame)-1]
+}
+
+func (f RawFrame) CRC() uint8 {
+	return f.Frame[len(f.Frame)-1]
+}
+
+// Decoder reads frames from an io.Reader through a fixed ring buffer without allocating per frame.
+// Bytes that are not a sync byte, frames with an impossible length and frames failing their crc
+// are skipped one byte at a time so a real frame hidden behind noise is still found.
+type Decoder struct {
+	r   io.Reader
+	err error // read error returned once the buffered bytes run out
+
+	ring  [decoderRingSize]byte
+	start uint // position of the first buffered byte, positions only grow and are masked into ring
+	end   uint
+
+	frame [MaxFrameLength]byte // holds frames that wrap around the end of ring
+
+	skipping bool
+	resyncs  uint64
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Reset discards everything buffered and reads from r, so a decoder can be reused without allocating
+func (d *Decoder) Reset(r io.Reader) {
+	d.r = r
+	d.err = nil
+	d.start = 0
+	d.end = 0
+	d.skipping = false
+	d.resyncs = 0
+}
+
+// Decode returns the next frame.
+// A frame with a bad crc is returned along with an error wrapping ErrInvalidCRC8 and a bad length returns an
+// error wrapping ErrFrameLength, in both cases Decode can be called again to continue with the following bytes.
+// Read errors, including io.EOF, are returned once every buffered frame has been decoded.
+func (d *Decoder) Decode() (RawFrame, error) {
+	for {
+		err := d.fill(2)
+		if err != nil {
+			return RawFrame{}, err
+		}
+
+		address := AddressType(d.at(0))
+		if !address.IsSync() {
+			if !d.skipping {
+				d.skipping = true
+				d.resyncs++
+			}
+			d.start++
+			continue
+		}
+		d.skipping = false
+
+		//[sync] [len] [type] [payload] [crc8], length covers type + payload + crc
+		length := int(d.at(1))
+		if length < MinFrameBody || length > MaxFrameBody {
+			d.start++
+			return RawFrame{}, fmt.Errorf("%w: %d", ErrFrameLength, length)
+		}
+
+		err = d.fill(2 + length)
+		if err != nil {
+			return RawFrame{}, err
+		}
+
+		frame := RawFrame{
+			Address: address,
+			Frame:   d.bytes(2, length),
+		}
+		if !ValidateFrame(frame.Frame) {
+			d.start++ //the sync byte may have been noise, look for another one inside this frame
+			return frame, fmt.Errorf("%w: %s", ErrInvalidCRC8, frame.Type().String())
+		}
+		d.start += uint(2 + length)
+		return frame, nil
+	}
+}
+
+// Resyncs is how many times bytes were skipped looking for a sync byte
+func (d *Decoder) Resyncs() uint64 {
+	return d.resyncs
+}
+
+// Buffered is how many bytes have been read but not decoded yet
+func (d *Decoder) Buffered() int {
+	return int(d.end - d.start)
+}
+
+func (d *Decoder) at(offset int) byte {
+	return d.ring[(d.start+uint(offset))&decoderRingMask]
+}
+
+// bytes returns length buffered bytes starting at offset, in place when they do not wrap around the ring
+func (d *Decoder) bytes(offset, length int) []byte {
+	first := int((d.start + uint(offset)) & decoderRingMask)
+	if first+length <= decoderRingSize {
+		return d.ring[first : first+length]
+	}
+	n := copy(d.frame[:], d.ring[first:])
+	copy(d.frame[n:length], d.ring[:length-n])
+	return d.frame[:length]
+}
+
+// fill reads until at least n bytes are buffered
+func (d *Decoder) fill(n int) error {
+	emptyReads := 0
+	for d.Buffered() < n {
+		if d.err != nil {
+			return d.err
+		}
+
+		next := int(d.end & decoderRingMask)
+		space := min(decoderRingSize-d.Buffered(), decoderRingSize-next)
+		read, err := d.r.Read(d.ring[next : next+space])
+		d.end += uint(read)
+		if err != nil {
+			d.err = err
+			continue
+		}
+
+		if read == 0 {
+			emptyReads++
+			if emptyReads >= maxConsecutiveEmptyReads {
+				return io.ErrNoProgress
+			}
+			continue
+		}
+		emptyReads = 0
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package frames
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type testFrame struct {
+	address   AddressType
+	frameType FrameType
+	payload   []byte
+}
+
+var syncAddresses = []AddressType{
+	AddressTypeFlightController,
+	AddressTypeRadioTransmitter,
+	AddressTypeReceiver,
+	AddressTypeTransmitter,
+}
+
+// framesFromBytes splits fuzz input into frames of [type][length][payload...]
+func framesFromBytes(data []byte) []testFrame {
+	var list []testFrame
+	for len(data) >= 2 {
+		frame := testFrame{
+			address:   syncAddresses[int(data[0])%len(syncAddresses)],
+			frameType: FrameType(data[0]),
+		}
+		length := min(int(data[1])%(MaxPayloadLength+1), len(data)-2)
+		frame.payload = data[2 : 2+length]
+		data = data[2+length:]
+		list = append(list, frame)
+	}
+	return list
+}
+
+// cleanFrame builds a frame whose only sync byte is its address so it can not hide another frame
+func cleanFrame(payload []byte) []byte {
+	payload = bytes.Clone(payload[:min(len(payload), MaxPayloadLength)])
+	for i := range payload {
+		if AddressType(payload[i]).IsSync() {
+			payload[i] = 0
+		}
+	}
+	for {
+		frame, err := AppendFrame(nil, AddressTypeFlightController, FrameTypeFlightMode, payload)
+		if err != nil {
+			panic(err)
+		}
+		if !AddressType(frame[len(frame)-1]).IsSync() {
+			return frame
+		}
+		payload = append(payload, 0) //change the crc
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add([]byte{0x02, 15, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, []byte{0xC8, 0x00, 0x01})
+	f.Add([]byte{0x21, 5, 'A', 'C', 'R', 'O', 0}, []byte{0xC8, 0x05, 0x21, 0xC8, 0x3E})
+	f.Add([]byte{0x28, 2, 0xC8, 0xEA}, []byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte, noise []byte) {
+		//frames built by AppendFrame decode back to the same frames
+		var stream []byte
+		want := framesFromBytes(data)
+		for _, frame := range want {
+			var err error
+			stream, err = AppendFrame(stream, frame.address, frame.frameType, frame.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		decoder := NewDecoder(bytes.NewReader(stream))
+		for i, frame := range want {
+			got, err := decoder.Decode()
+			if err != nil {
+				t.Fatalf("frame %d: %v", i, err)
+			}
+			if got.Address != frame.address || got.Type() != frame.frameType || !bytes.Equal(got.Payload(), frame.payload) {
+				t.Fatalf("frame %d: got %s %s %x want %s %s %x", i, got.Address, got.Type(), got.Payload(), frame.address, frame.frameType, frame.payload)
+			}
+		}
+		_, err := decoder.Decode()
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("expected io.EOF after the last frame, got %v", err)
+		}
+
+		//noise never hides valid frames following it. A false frame starting in the noise ends at most
+		//MaxFrameLength-1 bytes later, so repeat the valid frame past that and the last one must decode.
+		valid := cleanFrame(data)
+		stream = bytes.Clone(noise)
+		copies := MaxFrameLength/len(valid) + 2
+		for range copies {
+			stream = append(stream, valid...)
+		}
+
+		decoder = NewDecoder(bytes.NewReader(stream))
+		var last RawFrame
+		decoded := 0
+		for {
+			frame, err := decoder.Decode()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				continue
+			}
+			last = RawFrame{Address: frame.Address, Frame: bytes.Clone(frame.Frame)}
+			decoded++
+		}
+		if decoded == 0 || !bytes.Equal(last.Frame, valid[2:]) {
+			t.Fatalf("valid frame lost after noise %x: last decoded %x", noise, last.Frame)
+		}
+	})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	var stream []byte
+	gps := NewGpsData(47.397742, 8.545594, 12.3, 45, 500, 9)
+	battery := NewBatterySensorData(12.6, 3.5, 1234, 77)
+	attitude := NewAttitudeData(10, -5, 90)
+	for _, frame := range []Frame{&gps, &battery, &attitude} {
+		stream, _ = AppendFrame(stream, AddressTypeFlightController, frame.Type(), frame.MarshalPayload())
+	}
+	for len(stream) < 64*1024 {
+		stream = append(stream, stream...)
+	}
+
+	reader := bytes.NewReader(stream)
+	decoder := NewDecoder(reader)
+	b.SetBytes(int64(len(stream)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		reader.Reset(stream)
+		decoder.Reset(reader)
+		for {
+			_, err := decoder.Decode()
+			if err != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// https://github.com/crsf-wg/crsf/wiki/CRSF_FRAMETYPE_DEVICE_INFO
+package frames
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	DeviceInfoFieldsLength = 4 + 4 + 4 + 1 + 1 //Serial + Hardware + Software + Parameter Count + Protocol Version
+)
+
+type DeviceInfoData struct {
+	Name            string `json:"name"`             //null terminated
+	SerialNumber    uint32 `json:"serial_number"`    //big-endian, ELRS devices send "ELRS"
+	HardwareVersion uint32 `json:"hardware_version"` //big-endian
+	SoftwareVersion uint32 `json:"software_version"` //big-endian
+	ParameterCount  uint8  `json:"parameter_count"`
+	ProtocolVersion uint8  `json:"protocol_version"` //parameter protocol version
+}
+
+// UnmarshalDeviceInfo decodes the payload of an extended DEVICE_INFO frame
+func UnmarshalDeviceInfo(payload []byte) (DeviceInfoData, error) {
+	d := DeviceInfoData{}
+
+	nameEnd := bytes.IndexByte(payload, 0x00)
+	if nameEnd < 0 || len(payload) != nameEnd+1+DeviceInfoFieldsLength {
+		return d, ErrFrameLength
+	}
+
+	d.Name = string(payload[:nameEnd])
+	fields := payload[nameEnd+1:]
+	d.SerialNumber = binary.BigEndian.Uint32(fields[0:4])
+	d.HardwareVersion = binary.BigEndian.Uint32(fields[4:8])
+	d.SoftwareVersion = binary.BigEndian.Uint32(fields[8:12])
+	d.ParameterCount = fields[12]
+	d.ProtocolVersion = fields[13]
+	return d, nil
+}
+
+// MarshalDeviceInfo returns the payload of an extended DEVICE_INFO frame
+func (d *DeviceInfoData) MarshalDeviceInfo() []byte {
+	payload := make([]byte, len(d.Name)+1+DeviceInfoFieldsLength)
+	copy(payload, d.Name)
+
+	fields := payload[len(d.Name)+1:]
+	binary.BigEndian.PutUint32(fields[0:4], d.SerialNumber)
+	binary.BigEndian.PutUint32(fields[4:8], d.HardwareVersion)
+	binary.BigEndian.PutUint32(fields[8:12], d.SoftwareVersion)
+	fields[12] = d.ParameterCount
+	fields[13] = d.ProtocolVersion
+	return payload
+}
+
+func (d *DeviceInfoData) Type() FrameType {
+	return FrameTypeDeviceInfo
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *DeviceInfoData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return d.MarshalDeviceInfo()
+}
+
+func (d *DeviceInfoData) String() string {
+	return fmt.Sprintf("Name: %s Serial: 0x%08X Hardware: 0x%08X Software: 0x%08X Parameters: %d Protocol: %d",
+		d.Name,
+		d.SerialNumber,
+		d.HardwareVersion,
+		d.SoftwareVersion,
+		d.ParameterCount,
+		d.ProtocolVersion,
+	)
+}
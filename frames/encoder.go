@@ -0,0 +1,64 @@
+package frames
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxPayloadLength is the most payload bytes a frame can carry, extended frames include the destination and origin
+const MaxPayloadLength = MaxFrameBody - 2
+
+// Frame is a frame that can be encoded, every *Data type implements it.
+// The *Data types carried in extended frames implement ExtendedData and become a Frame in an AddressedFrame,
+// since their payload starts with the destination and origin.
+type Frame interface {
+	Type() FrameType
+	MarshalPayload() []byte // bytes between the type and the crc
+}
+
+// AppendFrame appends the on wire frame [address][len][type][payload][crc] to dst
+func AppendFrame(dst []byte, address AddressType, frameType FrameType, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadLength {
+		return dst, fmt.Errorf("%w: payload of %d bytes is over %d", ErrFrameLength, len(payload), MaxPayloadLength)
+	}
+
+	start := len(dst)
+	dst = append(dst, byte(address), byte(len(payload)+MinFrameBody), byte(frameType))
+	dst = append(dst, payload...)
+	dst = append(dst, GenerateCrc8Value(dst[start+2:]))
+	return dst, nil
+}
+
+// Encoder writes complete frames to an io.Writer, each frame is written with a single Write
+type Encoder struct {
+	w   io.Writer
+	buf [MaxFrameLength]byte
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes frame with address as the sync byte,
+// AddressTypeFlightController for telemetry or AddressTypeTransmitter for channels sent to a TX module
+func (e *Encoder) Encode(address AddressType, frame Frame) error {
+	return e.EncodeRaw(address, frame.Type(), frame.MarshalPayload())
+}
+
+// EncodeExtended writes an extended frame with its destination and origin
+func (e *Encoder) EncodeExtended(address AddressType, frame ExtendedFrame) error {
+	return e.EncodeRaw(address, frame.Type, frame.MarshalExtendedFrame())
+}
+
+// EncodeRaw writes a frame of any type from its payload
+func (e *Encoder) EncodeRaw(address AddressType, frameType FrameType, payload []byte) error {
+	frame, err := AppendFrame(e.buf[:0], address, frameType, payload)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(frame)
+	if err != nil {
+		return fmt.Errorf("failed writing frame: %w", err)
+	}
+	return nil
+}
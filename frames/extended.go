@@ -0,0 +1,91 @@
+// https://github.com/crsf-wg/crsf/wiki/Packet-Types
+package frames
+
+import (
+	"fmt"
+)
+
+const (
+	ExtendedFrameTypeStart = 0x28
+	ExtendedHeaderLength   = 3     //Type + Destination + Origin
+	MinExtendedFrameLength = 2 + 2 //Destination + Origin + Type + CRC
+)
+
+// ExtendedFrame is a frame type of 0x28 or above, addressed from one device to another
+type ExtendedFrame struct {
+	Type        FrameType   `json:"type"`
+	Destination AddressType `json:"destination"`
+	Origin      AddressType `json:"origin"`
+	Payload     []byte      `json:"payload"` //bytes after the origin, without the crc
+}
+
+func UnmarshalExtendedFrame(data []byte) (ExtendedFrame, error) {
+	f := ExtendedFrame{}
+	if len(data) < MinExtendedFrameLength {
+		return f, ErrFrameLength
+	}
+
+	if !ValidateFrame(data) {
+		return f, ErrInvalidCRC8
+	}
+
+	f.Type = FrameType(data[0])
+	if !f.Type.IsExtended() {
+		return f, fmt.Errorf("frame type %s is not extended", f.Type.String())
+	}
+
+	f.Destination = AddressType(data[1])
+	f.Origin = AddressType(data[2])
+	f.Payload = data[ExtendedHeaderLength : len(data)-1]
+	return f, nil
+}
+
+// MarshalExtendedFrame returns the bytes following the type: destination, origin and payload
+func (f *ExtendedFrame) MarshalExtendedFrame() []byte {
+	payload := make([]byte, 2+len(f.Payload))
+	payload[0] = byte(f.Destination)
+	payload[1] = byte(f.Origin)
+	copy(payload[2:], f.Payload)
+	return payload
+}
+
+// ExtendedData is implemented by every *Data type carried in an extended frame,
+// wrap it in an AddressedFrame with the destination and origin to get a Frame
+type ExtendedData interface {
+	Type() FrameType
+	MarshalExtendedPayload(destination, origin AddressType) []byte // bytes after the origin
+}
+
+// AddressedFrame is ExtendedData sent from Origin to Destination, it implements Frame
+type AddressedFrame struct {
+	Destination AddressType
+	Origin      AddressType
+	Data        ExtendedData
+}
+
+func NewAddressedFrame(destination, origin AddressType, data ExtendedData) AddressedFrame {
+	return AddressedFrame{Destination: destination, Origin: origin, Data: data}
+}
+
+func (f *AddressedFrame) Type() FrameType {
+	return f.Data.Type()
+}
+
+// MarshalPayload implements Frame, the payload starts with the destination and origin
+func (f *AddressedFrame) MarshalPayload() []byte {
+	frame := f.ExtendedFrame()
+	return frame.MarshalExtendedFrame()
+}
+
+func (f *AddressedFrame) ExtendedFrame() ExtendedFrame {
+	return ExtendedFrame{
+		Type:        f.Data.Type(),
+		Destination: f.Destination,
+		Origin:      f.Origin,
+		Payload:     f.Data.MarshalExtendedPayload(f.Destination, f.Origin),
+	}
+}
+
+func (f *ExtendedFrame) String() string {
+	return fmt.Sprintf("Type: %s Destination: %s Origin: %s Payload: %v", f.Type.String(), f.Destination.String(), f.Origin.String(), f.Payload)
+}
@@ -10,7 +10,14 @@ const (
 )
 
 type FlightModeData struct {
-	FlightMode string //max length 13
+	FlightMode string `json:"flight_mode"` //max length 13
+}
+
+// NewFlightModeData builds flight mode telemetry, names longer than 13 characters are cut short when marshalled
+func NewFlightModeData(flightMode string) FlightModeData {
+	return FlightModeData{
+		FlightMode: flightMode,
+	}
 }
 
 func UnmarshalFlightMode(data []byte) (FlightModeData, error) {
@@ -23,17 +30,36 @@ func UnmarshalFlightMode(data []byte) (FlightModeData, error) {
 	}
 	//TODO check correct type?
 
-	for i := range data[1 : len(data)-1] {
-		if i == 0x00 { //null terminator for string
+	for _, b := range data[1 : len(data)-1] {
+		if b == 0x00 { //null terminator for string
 			break
 		}
-		d.FlightMode += string(data[i])
+		d.FlightMode += string(b)
 	}
 
 	//TODO CRC byte?
 	return d, nil
 }
 
+func (d *FlightModeData) MarshalFlightMode() []byte {
+	flightMode := d.FlightMode
+	if len(flightMode) > FlightModeFrameLength-3 {
+		flightMode = flightMode[:FlightModeFrameLength-3] //leave room for the null terminator
+	}
+	payload := make([]byte, len(flightMode)+1)
+	copy(payload, flightMode)
+	return payload
+}
+
+func (d *FlightModeData) Type() FrameType {
+	return FrameTypeFlightMode
+}
+
+// MarshalPayload implements Frame
+func (d *FlightModeData) MarshalPayload() []byte {
+	return d.MarshalFlightMode()
+}
+
 func (d *FlightModeData) String() string {
 	return fmt.Sprintf("FlightMode: %s", d.FlightMode)
 }
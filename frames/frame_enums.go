@@ -1,18 +1,41 @@
 package frames
 
 //Run below command to generate model_enum file
-//go-enum -f ./data_enums.go
+//go-enum --marshal -f ./frame_enums.go
 
 /*
 ENUM(
+
+	Broadcast = 0x00, //extended frames sent to every device
+	USB = 0x10,
+	TBSCorePNPPro = 0x80,
+	Reserved1 = 0x8A,
+	CurrentSensor = 0xC0,
+	GPS = 0xC2,
+	TBSBlackbox = 0xC4,
 	FlightController = 0xC8, //Most should have this address
+	Reserved2 = 0xCA,
+	RaceTag = 0xCC,
+	VTX = 0xCE,
 	RadioTransmitter = 0xEA,
 	Receiver = 0xEC,
-	Transmitter = 0xEE //channels should have this address
+	Transmitter = 0xEE, //channels should have this address
+	ELRSLua = 0xEF
+
 )
 */
 type AddressType byte
 
+// IsSync reports if the address can start a frame on the wire
+func (x AddressType) IsSync() bool {
+	switch x {
+	case AddressTypeFlightController, AddressTypeRadioTransmitter, AddressTypeReceiver, AddressTypeTransmitter:
+		return true
+	default:
+		return false
+	}
+}
+
 /*
 ENUM(
 GPS = 0x02
@@ -26,6 +49,66 @@ LinkRx = 0x1C
 LinkTx = 0x1D
 Attitude = 0x1E
 FlightMode = 0x21
+DevicePing = 0x28
+DeviceInfo = 0x29
+ParameterSettingsEntry = 0x2B
+ParameterRead = 0x2C
+ParameterWrite = 0x2D
+Command = 0x32
+MspReq = 0x7A
+MspResp = 0x7B
+MspWrite = 0x7C
 )
 */
 type FrameType byte
+
+// IsExtended reports if frames of this type carry destination and origin addresses after the type
+func (x FrameType) IsExtended() bool {
+	return x >= ExtendedFrameTypeStart
+}
+
+/*
+ENUM(
+Uint8 = 0
+Int8 = 1
+Uint16 = 2
+Int16 = 3
+Uint32 = 4
+Int32 = 5
+Float = 8
+TextSelection = 9
+String = 10
+Folder = 11
+Info = 12
+Command = 13
+OutOfRange = 127
+)
+*/
+type ParameterType byte
+
+/*
+ENUM(
+Ready = 0
+Start = 1
+Progress = 2
+ConfirmationNeeded = 3
+Confirm = 4
+Cancel = 5
+Poll = 6
+)
+*/
+type CommandStatus byte
+
+/*
+ENUM(
+FC = 0x01
+Bluetooth = 0x03
+OSD = 0x05
+VTX = 0x08
+LED = 0x09
+General = 0x0A
+Crossfire = 0x10
+ACK = 0xFF
+)
+*/
+type CommandRealm byte
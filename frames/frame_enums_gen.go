@@ -12,27 +12,61 @@ import (
 )
 
 const (
+	// AddressTypeBroadcast is a AddressType of type Broadcast.
+	// extended frames sent to every device
+	AddressTypeBroadcast AddressType = iota
+	// AddressTypeUSB is a AddressType of type USB.
+	AddressTypeUSB AddressType = iota + 15
+	// AddressTypeTBSCorePNPPro is a AddressType of type TBSCorePNPPro.
+	AddressTypeTBSCorePNPPro AddressType = iota + 126
+	// AddressTypeReserved1 is a AddressType of type Reserved1.
+	AddressTypeReserved1 AddressType = iota + 135
+	// AddressTypeCurrentSensor is a AddressType of type CurrentSensor.
+	AddressTypeCurrentSensor AddressType = iota + 188
+	// AddressTypeGPS is a AddressType of type GPS.
+	AddressTypeGPS AddressType = iota + 189
+	// AddressTypeTBSBlackbox is a AddressType of type TBSBlackbox.
+	AddressTypeTBSBlackbox AddressType = iota + 190
 	// AddressTypeFlightController is a AddressType of type FlightController.
 	// Most should have this address
-	AddressTypeFlightController AddressType = iota + 200
+	AddressTypeFlightController AddressType = iota + 193
+	// AddressTypeReserved2 is a AddressType of type Reserved2.
+	AddressTypeReserved2 AddressType = iota + 194
+	// AddressTypeRaceTag is a AddressType of type RaceTag.
+	AddressTypeRaceTag AddressType = iota + 195
+	// AddressTypeVTX is a AddressType of type VTX.
+	AddressTypeVTX AddressType = iota + 196
 	// AddressTypeRadioTransmitter is a AddressType of type RadioTransmitter.
-	AddressTypeRadioTransmitter AddressType = iota + 233
+	AddressTypeRadioTransmitter AddressType = iota + 223
 	// AddressTypeReceiver is a AddressType of type Receiver.
-	AddressTypeReceiver AddressType = iota + 234
+	AddressTypeReceiver AddressType = iota + 224
 	// AddressTypeTransmitter is a AddressType of type Transmitter.
 	// channels should have this address
-	AddressTypeTransmitter AddressType = iota + 235
+	AddressTypeTransmitter AddressType = iota + 225
+	// AddressTypeELRSLua is a AddressType of type ELRSLua.
+	AddressTypeELRSLua
 )
 
 var ErrInvalidAddressType = errors.New("not a valid AddressType")
 
-const _AddressTypeName = "FlightControllerRadioTransmitterReceiverTransmitter"
+const _AddressTypeName = "BroadcastUSBTBSCorePNPProReserved1CurrentSensorGPSTBSBlackboxFlightControllerReserved2RaceTagVTXRadioTransmitterReceiverTransmitterELRSLua"
 
 var _AddressTypeMap = map[AddressType]string{
-	AddressTypeFlightController: _AddressTypeName[0:16],
-	AddressTypeRadioTransmitter: _AddressTypeName[16:32],
-	AddressTypeReceiver:         _AddressTypeName[32:40],
-	AddressTypeTransmitter:      _AddressTypeName[40:51],
+	AddressTypeBroadcast:        _AddressTypeName[0:9],
+	AddressTypeUSB:              _AddressTypeName[9:12],
+	AddressTypeTBSCorePNPPro:    _AddressTypeName[12:25],
+	AddressTypeReserved1:        _AddressTypeName[25:34],
+	AddressTypeCurrentSensor:    _AddressTypeName[34:47],
+	AddressTypeGPS:              _AddressTypeName[47:50],
+	AddressTypeTBSBlackbox:      _AddressTypeName[50:61],
+	AddressTypeFlightController: _AddressTypeName[61:77],
+	AddressTypeReserved2:        _AddressTypeName[77:86],
+	AddressTypeRaceTag:          _AddressTypeName[86:93],
+	AddressTypeVTX:              _AddressTypeName[93:96],
+	AddressTypeRadioTransmitter: _AddressTypeName[96:112],
+	AddressTypeReceiver:         _AddressTypeName[112:120],
+	AddressTypeTransmitter:      _AddressTypeName[120:131],
+	AddressTypeELRSLua:          _AddressTypeName[131:138],
 }
 
 // String implements the Stringer interface.
@@ -51,10 +85,21 @@ func (x AddressType) IsValid() bool {
 }
 
 var _AddressTypeValue = map[string]AddressType{
-	_AddressTypeName[0:16]:  AddressTypeFlightController,
-	_AddressTypeName[16:32]: AddressTypeRadioTransmitter,
-	_AddressTypeName[32:40]: AddressTypeReceiver,
-	_AddressTypeName[40:51]: AddressTypeTransmitter,
+	_AddressTypeName[0:9]:     AddressTypeBroadcast,
+	_AddressTypeName[9:12]:    AddressTypeUSB,
+	_AddressTypeName[12:25]:   AddressTypeTBSCorePNPPro,
+	_AddressTypeName[25:34]:   AddressTypeReserved1,
+	_AddressTypeName[34:47]:   AddressTypeCurrentSensor,
+	_AddressTypeName[47:50]:   AddressTypeGPS,
+	_AddressTypeName[50:61]:   AddressTypeTBSBlackbox,
+	_AddressTypeName[61:77]:   AddressTypeFlightController,
+	_AddressTypeName[77:86]:   AddressTypeReserved2,
+	_AddressTypeName[86:93]:   AddressTypeRaceTag,
+	_AddressTypeName[93:96]:   AddressTypeVTX,
+	_AddressTypeName[96:112]:  AddressTypeRadioTransmitter,
+	_AddressTypeName[112:120]: AddressTypeReceiver,
+	_AddressTypeName[120:131]: AddressTypeTransmitter,
+	_AddressTypeName[131:138]: AddressTypeELRSLua,
 }
 
 // ParseAddressType attempts to convert a string to a AddressType.
@@ -65,6 +110,186 @@ func ParseAddressType(name string) (AddressType, error) {
 	return AddressType(0), fmt.Errorf("%s is %w", name, ErrInvalidAddressType)
 }
 
+// MarshalText implements the text marshaller method.
+func (x AddressType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *AddressType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseAddressType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// CommandRealmFC is a CommandRealm of type FC.
+	CommandRealmFC CommandRealm = iota + 1
+	// CommandRealmBluetooth is a CommandRealm of type Bluetooth.
+	CommandRealmBluetooth CommandRealm = iota + 2
+	// CommandRealmOSD is a CommandRealm of type OSD.
+	CommandRealmOSD CommandRealm = iota + 3
+	// CommandRealmVTX is a CommandRealm of type VTX.
+	CommandRealmVTX CommandRealm = iota + 5
+	// CommandRealmLED is a CommandRealm of type LED.
+	CommandRealmLED
+	// CommandRealmGeneral is a CommandRealm of type General.
+	CommandRealmGeneral
+	// CommandRealmCrossfire is a CommandRealm of type Crossfire.
+	CommandRealmCrossfire CommandRealm = iota + 10
+	// CommandRealmACK is a CommandRealm of type ACK.
+	CommandRealmACK CommandRealm = iota + 248
+)
+
+var ErrInvalidCommandRealm = errors.New("not a valid CommandRealm")
+
+const _CommandRealmName = "FCBluetoothOSDVTXLEDGeneralCrossfireACK"
+
+var _CommandRealmMap = map[CommandRealm]string{
+	CommandRealmFC:        _CommandRealmName[0:2],
+	CommandRealmBluetooth: _CommandRealmName[2:11],
+	CommandRealmOSD:       _CommandRealmName[11:14],
+	CommandRealmVTX:       _CommandRealmName[14:17],
+	CommandRealmLED:       _CommandRealmName[17:20],
+	CommandRealmGeneral:   _CommandRealmName[20:27],
+	CommandRealmCrossfire: _CommandRealmName[27:36],
+	CommandRealmACK:       _CommandRealmName[36:39],
+}
+
+// String implements the Stringer interface.
+func (x CommandRealm) String() string {
+	if str, ok := _CommandRealmMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("CommandRealm(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x CommandRealm) IsValid() bool {
+	_, ok := _CommandRealmMap[x]
+	return ok
+}
+
+var _CommandRealmValue = map[string]CommandRealm{
+	_CommandRealmName[0:2]:   CommandRealmFC,
+	_CommandRealmName[2:11]:  CommandRealmBluetooth,
+	_CommandRealmName[11:14]: CommandRealmOSD,
+	_CommandRealmName[14:17]: CommandRealmVTX,
+	_CommandRealmName[17:20]: CommandRealmLED,
+	_CommandRealmName[20:27]: CommandRealmGeneral,
+	_CommandRealmName[27:36]: CommandRealmCrossfire,
+	_CommandRealmName[36:39]: CommandRealmACK,
+}
+
+// ParseCommandRealm attempts to convert a string to a CommandRealm.
+func ParseCommandRealm(name string) (CommandRealm, error) {
+	if x, ok := _CommandRealmValue[name]; ok {
+		return x, nil
+	}
+	return CommandRealm(0), fmt.Errorf("%s is %w", name, ErrInvalidCommandRealm)
+}
+
+// MarshalText implements the text marshaller method.
+func (x CommandRealm) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *CommandRealm) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseCommandRealm(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// CommandStatusReady is a CommandStatus of type Ready.
+	CommandStatusReady CommandStatus = iota
+	// CommandStatusStart is a CommandStatus of type Start.
+	CommandStatusStart
+	// CommandStatusProgress is a CommandStatus of type Progress.
+	CommandStatusProgress
+	// CommandStatusConfirmationNeeded is a CommandStatus of type ConfirmationNeeded.
+	CommandStatusConfirmationNeeded
+	// CommandStatusConfirm is a CommandStatus of type Confirm.
+	CommandStatusConfirm
+	// CommandStatusCancel is a CommandStatus of type Cancel.
+	CommandStatusCancel
+	// CommandStatusPoll is a CommandStatus of type Poll.
+	CommandStatusPoll
+)
+
+var ErrInvalidCommandStatus = errors.New("not a valid CommandStatus")
+
+const _CommandStatusName = "ReadyStartProgressConfirmationNeededConfirmCancelPoll"
+
+var _CommandStatusMap = map[CommandStatus]string{
+	CommandStatusReady:              _CommandStatusName[0:5],
+	CommandStatusStart:              _CommandStatusName[5:10],
+	CommandStatusProgress:           _CommandStatusName[10:18],
+	CommandStatusConfirmationNeeded: _CommandStatusName[18:36],
+	CommandStatusConfirm:            _CommandStatusName[36:43],
+	CommandStatusCancel:             _CommandStatusName[43:49],
+	CommandStatusPoll:               _CommandStatusName[49:53],
+}
+
+// String implements the Stringer interface.
+func (x CommandStatus) String() string {
+	if str, ok := _CommandStatusMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("CommandStatus(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x CommandStatus) IsValid() bool {
+	_, ok := _CommandStatusMap[x]
+	return ok
+}
+
+var _CommandStatusValue = map[string]CommandStatus{
+	_CommandStatusName[0:5]:   CommandStatusReady,
+	_CommandStatusName[5:10]:  CommandStatusStart,
+	_CommandStatusName[10:18]: CommandStatusProgress,
+	_CommandStatusName[18:36]: CommandStatusConfirmationNeeded,
+	_CommandStatusName[36:43]: CommandStatusConfirm,
+	_CommandStatusName[43:49]: CommandStatusCancel,
+	_CommandStatusName[49:53]: CommandStatusPoll,
+}
+
+// ParseCommandStatus attempts to convert a string to a CommandStatus.
+func ParseCommandStatus(name string) (CommandStatus, error) {
+	if x, ok := _CommandStatusValue[name]; ok {
+		return x, nil
+	}
+	return CommandStatus(0), fmt.Errorf("%s is %w", name, ErrInvalidCommandStatus)
+}
+
+// MarshalText implements the text marshaller method.
+func (x CommandStatus) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *CommandStatus) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseCommandStatus(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
 const (
 	// FrameTypeGPS is a FrameType of type GPS.
 	FrameTypeGPS FrameType = iota + 2
@@ -88,24 +313,51 @@ const (
 	FrameTypeAttitude
 	// FrameTypeFlightMode is a FrameType of type FlightMode.
 	FrameTypeFlightMode FrameType = iota + 23
+	// FrameTypeDevicePing is a FrameType of type DevicePing.
+	FrameTypeDevicePing FrameType = iota + 29
+	// FrameTypeDeviceInfo is a FrameType of type DeviceInfo.
+	FrameTypeDeviceInfo
+	// FrameTypeParameterSettingsEntry is a FrameType of type ParameterSettingsEntry.
+	FrameTypeParameterSettingsEntry FrameType = iota + 30
+	// FrameTypeParameterRead is a FrameType of type ParameterRead.
+	FrameTypeParameterRead
+	// FrameTypeParameterWrite is a FrameType of type ParameterWrite.
+	FrameTypeParameterWrite
+	// FrameTypeCommand is a FrameType of type Command.
+	FrameTypeCommand FrameType = iota + 34
+	// FrameTypeMspReq is a FrameType of type MspReq.
+	FrameTypeMspReq FrameType = iota + 105
+	// FrameTypeMspResp is a FrameType of type MspResp.
+	FrameTypeMspResp
+	// FrameTypeMspWrite is a FrameType of type MspWrite.
+	FrameTypeMspWrite
 )
 
 var ErrInvalidFrameType = errors.New("not a valid FrameType")
 
-const _FrameTypeName = "GPSVarioBatterySensorBarometerLinkStatsChannelsChannelSubSetLinkRxLinkTxAttitudeFlightMode"
+const _FrameTypeName = "GPSVarioBatterySensorBarometerLinkStatsChannelsChannelSubSetLinkRxLinkTxAttitudeFlightModeDevicePingDeviceInfoParameterSettingsEntryParameterReadParameterWriteCommandMspReqMspRespMspWrite"
 
 var _FrameTypeMap = map[FrameType]string{
-	FrameTypeGPS:           _FrameTypeName[0:3],
-	FrameTypeVario:         _FrameTypeName[3:8],
-	FrameTypeBatterySensor: _FrameTypeName[8:21],
-	FrameTypeBarometer:     _FrameTypeName[21:30],
-	FrameTypeLinkStats:     _FrameTypeName[30:39],
-	FrameTypeChannels:      _FrameTypeName[39:47],
-	FrameTypeChannelSubSet: _FrameTypeName[47:60],
-	FrameTypeLinkRx:        _FrameTypeName[60:66],
-	FrameTypeLinkTx:        _FrameTypeName[66:72],
-	FrameTypeAttitude:      _FrameTypeName[72:80],
-	FrameTypeFlightMode:    _FrameTypeName[80:90],
+	FrameTypeGPS:                    _FrameTypeName[0:3],
+	FrameTypeVario:                  _FrameTypeName[3:8],
+	FrameTypeBatterySensor:          _FrameTypeName[8:21],
+	FrameTypeBarometer:              _FrameTypeName[21:30],
+	FrameTypeLinkStats:              _FrameTypeName[30:39],
+	FrameTypeChannels:               _FrameTypeName[39:47],
+	FrameTypeChannelSubSet:          _FrameTypeName[47:60],
+	FrameTypeLinkRx:                 _FrameTypeName[60:66],
+	FrameTypeLinkTx:                 _FrameTypeName[66:72],
+	FrameTypeAttitude:               _FrameTypeName[72:80],
+	FrameTypeFlightMode:             _FrameTypeName[80:90],
+	FrameTypeDevicePing:             _FrameTypeName[90:100],
+	FrameTypeDeviceInfo:             _FrameTypeName[100:110],
+	FrameTypeParameterSettingsEntry: _FrameTypeName[110:132],
+	FrameTypeParameterRead:          _FrameTypeName[132:145],
+	FrameTypeParameterWrite:         _FrameTypeName[145:159],
+	FrameTypeCommand:                _FrameTypeName[159:166],
+	FrameTypeMspReq:                 _FrameTypeName[166:172],
+	FrameTypeMspResp:                _FrameTypeName[172:179],
+	FrameTypeMspWrite:               _FrameTypeName[179:187],
 }
 
 // String implements the Stringer interface.
@@ -124,17 +376,26 @@ func (x FrameType) IsValid() bool {
 }
 
 var _FrameTypeValue = map[string]FrameType{
-	_FrameTypeName[0:3]:   FrameTypeGPS,
-	_FrameTypeName[3:8]:   FrameTypeVario,
-	_FrameTypeName[8:21]:  FrameTypeBatterySensor,
-	_FrameTypeName[21:30]: FrameTypeBarometer,
-	_FrameTypeName[30:39]: FrameTypeLinkStats,
-	_FrameTypeName[39:47]: FrameTypeChannels,
-	_FrameTypeName[47:60]: FrameTypeChannelSubSet,
-	_FrameTypeName[60:66]: FrameTypeLinkRx,
-	_FrameTypeName[66:72]: FrameTypeLinkTx,
-	_FrameTypeName[72:80]: FrameTypeAttitude,
-	_FrameTypeName[80:90]: FrameTypeFlightMode,
+	_FrameTypeName[0:3]:     FrameTypeGPS,
+	_FrameTypeName[3:8]:     FrameTypeVario,
+	_FrameTypeName[8:21]:    FrameTypeBatterySensor,
+	_FrameTypeName[21:30]:   FrameTypeBarometer,
+	_FrameTypeName[30:39]:   FrameTypeLinkStats,
+	_FrameTypeName[39:47]:   FrameTypeChannels,
+	_FrameTypeName[47:60]:   FrameTypeChannelSubSet,
+	_FrameTypeName[60:66]:   FrameTypeLinkRx,
+	_FrameTypeName[66:72]:   FrameTypeLinkTx,
+	_FrameTypeName[72:80]:   FrameTypeAttitude,
+	_FrameTypeName[80:90]:   FrameTypeFlightMode,
+	_FrameTypeName[90:100]:  FrameTypeDevicePing,
+	_FrameTypeName[100:110]: FrameTypeDeviceInfo,
+	_FrameTypeName[110:132]: FrameTypeParameterSettingsEntry,
+	_FrameTypeName[132:145]: FrameTypeParameterRead,
+	_FrameTypeName[145:159]: FrameTypeParameterWrite,
+	_FrameTypeName[159:166]: FrameTypeCommand,
+	_FrameTypeName[166:172]: FrameTypeMspReq,
+	_FrameTypeName[172:179]: FrameTypeMspResp,
+	_FrameTypeName[179:187]: FrameTypeMspWrite,
 }
 
 // ParseFrameType attempts to convert a string to a FrameType.
@@ -143,4 +404,124 @@ func ParseFrameType(name string) (FrameType, error) {
 		return x, nil
 	}
 	return FrameType(0), fmt.Errorf("%s is %w", name, ErrInvalidFrameType)
-}
\ No newline at end of file
+}
+
+// MarshalText implements the text marshaller method.
+func (x FrameType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *FrameType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseFrameType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// ParameterTypeUint8 is a ParameterType of type Uint8.
+	ParameterTypeUint8 ParameterType = iota
+	// ParameterTypeInt8 is a ParameterType of type Int8.
+	ParameterTypeInt8
+	// ParameterTypeUint16 is a ParameterType of type Uint16.
+	ParameterTypeUint16
+	// ParameterTypeInt16 is a ParameterType of type Int16.
+	ParameterTypeInt16
+	// ParameterTypeUint32 is a ParameterType of type Uint32.
+	ParameterTypeUint32
+	// ParameterTypeInt32 is a ParameterType of type Int32.
+	ParameterTypeInt32
+	// ParameterTypeFloat is a ParameterType of type Float.
+	ParameterTypeFloat ParameterType = iota + 2
+	// ParameterTypeTextSelection is a ParameterType of type TextSelection.
+	ParameterTypeTextSelection
+	// ParameterTypeString is a ParameterType of type String.
+	ParameterTypeString
+	// ParameterTypeFolder is a ParameterType of type Folder.
+	ParameterTypeFolder
+	// ParameterTypeInfo is a ParameterType of type Info.
+	ParameterTypeInfo
+	// ParameterTypeCommand is a ParameterType of type Command.
+	ParameterTypeCommand
+	// ParameterTypeOutOfRange is a ParameterType of type OutOfRange.
+	ParameterTypeOutOfRange ParameterType = iota + 115
+)
+
+var ErrInvalidParameterType = errors.New("not a valid ParameterType")
+
+const _ParameterTypeName = "Uint8Int8Uint16Int16Uint32Int32FloatTextSelectionStringFolderInfoCommandOutOfRange"
+
+var _ParameterTypeMap = map[ParameterType]string{
+	ParameterTypeUint8:         _ParameterTypeName[0:5],
+	ParameterTypeInt8:          _ParameterTypeName[5:9],
+	ParameterTypeUint16:        _ParameterTypeName[9:15],
+	ParameterTypeInt16:         _ParameterTypeName[15:20],
+	ParameterTypeUint32:        _ParameterTypeName[20:26],
+	ParameterTypeInt32:         _ParameterTypeName[26:31],
+	ParameterTypeFloat:         _ParameterTypeName[31:36],
+	ParameterTypeTextSelection: _ParameterTypeName[36:49],
+	ParameterTypeString:        _ParameterTypeName[49:55],
+	ParameterTypeFolder:        _ParameterTypeName[55:61],
+	ParameterTypeInfo:          _ParameterTypeName[61:65],
+	ParameterTypeCommand:       _ParameterTypeName[65:72],
+	ParameterTypeOutOfRange:    _ParameterTypeName[72:82],
+}
+
+// String implements the Stringer interface.
+func (x ParameterType) String() string {
+	if str, ok := _ParameterTypeMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ParameterType(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x ParameterType) IsValid() bool {
+	_, ok := _ParameterTypeMap[x]
+	return ok
+}
+
+var _ParameterTypeValue = map[string]ParameterType{
+	_ParameterTypeName[0:5]:   ParameterTypeUint8,
+	_ParameterTypeName[5:9]:   ParameterTypeInt8,
+	_ParameterTypeName[9:15]:  ParameterTypeUint16,
+	_ParameterTypeName[15:20]: ParameterTypeInt16,
+	_ParameterTypeName[20:26]: ParameterTypeUint32,
+	_ParameterTypeName[26:31]: ParameterTypeInt32,
+	_ParameterTypeName[31:36]: ParameterTypeFloat,
+	_ParameterTypeName[36:49]: ParameterTypeTextSelection,
+	_ParameterTypeName[49:55]: ParameterTypeString,
+	_ParameterTypeName[55:61]: ParameterTypeFolder,
+	_ParameterTypeName[61:65]: ParameterTypeInfo,
+	_ParameterTypeName[65:72]: ParameterTypeCommand,
+	_ParameterTypeName[72:82]: ParameterTypeOutOfRange,
+}
+
+// ParseParameterType attempts to convert a string to a ParameterType.
+func ParseParameterType(name string) (ParameterType, error) {
+	if x, ok := _ParameterTypeValue[name]; ok {
+		return x, nil
+	}
+	return ParameterType(0), fmt.Errorf("%s is %w", name, ErrInvalidParameterType)
+}
+
+// MarshalText implements the text marshaller method.
+func (x ParameterType) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *ParameterType) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseParameterType(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
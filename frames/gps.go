@@ -3,7 +3,9 @@ package frames
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 const (
@@ -11,12 +13,24 @@ const (
 )
 
 type GpsData struct {
-	Lat            int32 //latitude in degress * 10000000, big-endian
-	Long           int32
-	Speed          int16  //km/h * 10, big-endian
-	Course         int16  //gps heading in degress * 100, big-endian
-	Altitude       uint16 //gps altitude in meters + 1000 m, big-endian
-	SatelliteCount uint8
+	Lat            int32  `json:"lat"` //latitude in degress * 10000000, big-endian
+	Long           int32  `json:"long"`
+	Speed          int16  `json:"speed"`    //km/h * 10, big-endian
+	Course         int16  `json:"course"`   //gps heading in degress * 100, unsigned 0-35999 on the wire, big-endian
+	Altitude       uint16 `json:"altitude"` //gps altitude in meters + 1000 m, big-endian
+	SatelliteCount uint8  `json:"satellite_count"`
+}
+
+// NewGpsData builds GPS telemetry from degrees, km/h and meters
+func NewGpsData(latitude, longitude, speedKph, courseDegrees, altitudeMeters float64, satellites uint8) GpsData {
+	return GpsData{
+		Lat:            int32(math.Round(latitude * 10000000)),
+		Long:           int32(math.Round(longitude * 10000000)),
+		Speed:          int16(math.Round(speedKph * 10)),
+		Course:         int16(courseFromDegrees(courseDegrees)),
+		Altitude:       uint16(min(max(math.Round(altitudeMeters+1000), 0), math.MaxUint16)),
+		SatelliteCount: satellites,
+	}
 }
 
 func UnmarshalGps(data []byte) (GpsData, error) {
@@ -41,13 +55,89 @@ func UnmarshalGps(data []byte) (GpsData, error) {
 	return d, nil
 }
 
+func (d *GpsData) MarshalGps() []byte {
+	payload := make([]byte, GpsFrameLength-2)
+	binary.BigEndian.PutUint32(payload[0:4], uint32(d.Lat))
+	binary.BigEndian.PutUint32(payload[4:8], uint32(d.Long))
+	binary.BigEndian.PutUint16(payload[8:10], uint16(d.Speed))
+	binary.BigEndian.PutUint16(payload[10:12], uint16(d.Course))
+	binary.BigEndian.PutUint16(payload[12:14], d.Altitude)
+	payload[14] = d.SatelliteCount
+	return payload
+}
+
+func (d *GpsData) Type() FrameType {
+	return FrameTypeGPS
+}
+
+// MarshalPayload implements Frame
+func (d *GpsData) MarshalPayload() []byte {
+	return d.MarshalGps()
+}
+
 func (d *GpsData) String() string {
-	lat := float32(d.Lat) / 10000000
-	long := float32(d.Long) / 10000000
-	speed := float32(d.Speed) / 10
-	course := float32(d.Course) / 100
-	altitude := float32(d.Altitude) - 1000
-	satCount := d.SatelliteCount
-
-	return fmt.Sprintf("Lat: %.7f Long: %.7f Speed: %.1fkph Course: %.2f Altitude: %.0fm SatCount: %d", lat, long, speed, course, altitude, satCount)
+	return fmt.Sprintf("Lat: %.7f Long: %.7f Speed: %.1fkph Course: %.2f Altitude: %.0fm SatCount: %d",
+		d.Latitude(),
+		d.Longitude(),
+		d.SpeedKph(),
+		d.CourseDegrees(),
+		d.AltitudeMeters(),
+		d.SatelliteCount,
+	)
+}
+
+// Latitude in degrees
+func (d *GpsData) Latitude() float64 {
+	return float64(d.Lat) / 10000000
+}
+
+// Longitude in degrees
+func (d *GpsData) Longitude() float64 {
+	return float64(d.Long) / 10000000
+}
+
+func (d *GpsData) SpeedKph() float64 {
+	return float64(d.Speed) / 10
+}
+
+func (d *GpsData) SpeedMetersPerSecond() float64 {
+	return d.SpeedKph() / 3.6
+}
+
+// CourseDegrees is the heading in [0, 360)
+func (d *GpsData) CourseDegrees() float64 {
+	return float64(uint16(d.Course)) / 100
+}
+
+// courseFromDegrees wraps degrees into [0, 360) as the unsigned wire value
+func courseFromDegrees(degrees float64) uint16 {
+	course := math.Mod(math.Round(degrees*100), 36000)
+	if course < 0 {
+		course += 36000
+	}
+	return uint16(course)
+}
+
+func (d *GpsData) AltitudeMeters() float64 {
+	return float64(d.Altitude) - 1000
+}
+
+// MarshalJSON includes the raw fields and their values in degrees, km/h and meters
+func (d GpsData) MarshalJSON() ([]byte, error) {
+	type raw GpsData
+	return json.Marshal(struct {
+		raw
+		LatitudeDegrees  float64 `json:"latitude_deg"`
+		LongitudeDegrees float64 `json:"longitude_deg"`
+		SpeedKph         float64 `json:"speed_kph"`
+		CourseDegrees    float64 `json:"course_deg"`
+		AltitudeMeters   float64 `json:"altitude_m"`
+	}{
+		raw:              raw(d),
+		LatitudeDegrees:  d.Latitude(),
+		LongitudeDegrees: d.Longitude(),
+		SpeedKph:         d.SpeedKph(),
+		CourseDegrees:    d.CourseDegrees(),
+		AltitudeMeters:   d.AltitudeMeters(),
+	})
 }
@@ -10,10 +10,10 @@ const (
 )
 
 type LinkRxData struct {
-	RssiPercent int8
-	Unknown1    uint8
-	Unknown2    uint8
-	PowerIndex  int8
+	RssiPercent int8  `json:"rssi_percent"`
+	Unknown1    uint8 `json:"unknown1"`
+	Unknown2    uint8 `json:"unknown2"`
+	PowerIndex  int8  `json:"power_index"`
 }
 
 func UnmarshalLinkRx(data []byte) (LinkRxData, error) {
@@ -34,6 +34,24 @@ func UnmarshalLinkRx(data []byte) (LinkRxData, error) {
 	return d, nil
 }
 
+func (d *LinkRxData) MarshalLinkRx() []byte {
+	return []byte{
+		byte(d.RssiPercent),
+		d.Unknown1,
+		d.Unknown2,
+		byte(d.PowerIndex),
+	}
+}
+
+func (d *LinkRxData) Type() FrameType {
+	return FrameTypeLinkRx
+}
+
+// MarshalPayload implements Frame
+func (d *LinkRxData) MarshalPayload() []byte {
+	return d.MarshalLinkRx()
+}
+
 func (d *LinkRxData) String() string {
 	return fmt.Sprintf("RssiPercent: %d%% Unknown1: %d Unknown2: %d PowerIndex: %d", d.RssiPercent, d.Unknown1, d.Unknown2, d.PowerIndex)
 }
@@ -2,6 +2,7 @@
 package frames
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -9,17 +10,21 @@ const (
 	LinkStatsFrameLength = 10 + 2 //Payload + Type + CRC
 )
 
+// linkStatsPowerMilliwatts is indexed by LinkStatsData.Power, the CRSF tx power enum.
+// It is not sorted, 250mW and 50mW were appended to the enum after 2000mW.
+var linkStatsPowerMilliwatts = []int{0, 10, 25, 100, 500, 1000, 2000, 250, 50}
+
 type LinkStatsData struct {
-	UplinkRssiAnt1     uint8 //dBm * -1
-	UplinkRssiAnt2     uint8 //dBm * -1
-	UplinkQuality      uint8 // (0-100)%
-	UplinkSnr          int8  //db
-	DiversifyActiveAnt uint8 //( enum ant. 1 = 0, ant. 2 = 1 )
-	RfMode             uint8 //(500hz, 250hz etc... varies)
-	Power              uint8 // ( enum 0mW = 0, 10mW, 25 mW, 100 mW, 500 mW, 1000 mW, 2000mW, 50mW )
-	DownlinkRssi       uint8 //dBm * -1
-	DownlinkQuality    uint8 // (0-100)%
-	DownlinkSnr        uint8 //db
+	UplinkRssiAnt1     uint8 `json:"uplink_rssi_ant1"`     //dBm * -1
+	UplinkRssiAnt2     uint8 `json:"uplink_rssi_ant2"`     //dBm * -1
+	UplinkQuality      uint8 `json:"uplink_quality"`       // (0-100)%
+	UplinkSnr          int8  `json:"uplink_snr"`           //db
+	DiversifyActiveAnt uint8 `json:"diversity_active_ant"` //( enum ant. 1 = 0, ant. 2 = 1 )
+	RfMode             uint8 `json:"rf_mode"`              //(500hz, 250hz etc... varies)
+	Power              uint8 `json:"power"`                // ( enum 0mW = 0, 10mW, 25mW, 100mW, 500mW, 1000mW, 2000mW, 250mW, 50mW )
+	DownlinkRssi       uint8 `json:"downlink_rssi"`        //dBm * -1
+	DownlinkQuality    uint8 `json:"downlink_quality"`     // (0-100)%
+	DownlinkSnr        uint8 `json:"downlink_snr"`         //db
 }
 
 func UnmarshalLinkStats(data []byte) (LinkStatsData, error) {
@@ -46,33 +51,39 @@ func UnmarshalLinkStats(data []byte) (LinkStatsData, error) {
 	return d, nil
 }
 
-func (d *LinkStatsData) String() string {
-	txRssiAnt1 := int8(d.UplinkRssiAnt1) * -1
-	txRssiAnt2 := int8(d.UplinkRssiAnt2) * -1
-
-	activeAnt := d.DiversifyActiveAnt + 1
-
-	power := 0
-	switch d.Power {
-	case 0:
-		power = 0
-	case 1:
-		power = 10
-	case 2:
-		power = 25
-	case 3:
-		power = 100
-	case 4:
-		power = 500
-	case 5:
-		power = 1000
-	case 6:
-		power = 2000
-	case 7:
-		power = 50
+func (d *LinkStatsData) MarshalLinkStats() []byte {
+	return []byte{
+		d.UplinkRssiAnt1,
+		d.UplinkRssiAnt2,
+		d.UplinkQuality,
+		byte(d.UplinkSnr),
+		d.DiversifyActiveAnt,
+		d.RfMode,
+		d.Power,
+		d.DownlinkRssi,
+		d.DownlinkQuality,
+		d.DownlinkSnr,
 	}
+}
+
+func (d *LinkStatsData) Type() FrameType {
+	return FrameTypeLinkStats
+}
 
-	rxRssi := int8(d.DownlinkRssi) * -1
+// MarshalPayload implements Frame
+func (d *LinkStatsData) MarshalPayload() []byte {
+	return d.MarshalLinkStats()
+}
+
+func (d *LinkStatsData) String() string {
+	txRssiAnt1 := d.Rssi1DBm()
+	txRssiAnt2 := d.Rssi2DBm()
+
+	activeAnt := d.ActiveAntenna()
+
+	power := d.TxPowerMilliwatts()
+
+	rxRssi := d.DownlinkRssiDBm()
 
 	return fmt.Sprintf("TxRssiAnt1: %ddBm TxRssiAnt2: %ddBm TxQuality: %d%% TxSNR: %ddb ActiveAnt: %d RFMode: %dhz Power: %dmw RxRSSI: %ddBm RxQuality: %d%% RxSNR: %ddb",
 		txRssiAnt1,
@@ -87,3 +98,77 @@ func (d *LinkStatsData) String() string {
 		d.DownlinkSnr,
 	)
 }
+
+// MarshalJSON includes the raw fields and their values in dBm, dB and mW
+func (d LinkStatsData) MarshalJSON() ([]byte, error) {
+	type raw LinkStatsData
+	return json.Marshal(struct {
+		raw
+		UplinkRssiAnt1DBm  int   `json:"uplink_rssi_ant1_dbm"`
+		UplinkRssiAnt2DBm  int   `json:"uplink_rssi_ant2_dbm"`
+		UplinkQualityPct   uint8 `json:"uplink_quality_percent"`
+		UplinkSnrDB        int8  `json:"uplink_snr_db"`
+		ActiveAntenna      uint8 `json:"active_antenna"`
+		TxPowerMilliwatts  int   `json:"tx_power_mw"`
+		DownlinkRssiDBm    int   `json:"downlink_rssi_dbm"`
+		DownlinkQualityPct uint8 `json:"downlink_quality_percent"`
+		DownlinkSnrDB      int8  `json:"downlink_snr_db"`
+	}{
+		raw:                raw(d),
+		UplinkRssiAnt1DBm:  d.Rssi1DBm(),
+		UplinkRssiAnt2DBm:  d.Rssi2DBm(),
+		UplinkQualityPct:   d.UplinkQuality,
+		UplinkSnrDB:        d.UplinkSnr,
+		ActiveAntenna:      d.ActiveAntenna(),
+		TxPowerMilliwatts:  d.TxPowerMilliwatts(),
+		DownlinkRssiDBm:    d.DownlinkRssiDBm(),
+		DownlinkQualityPct: d.DownlinkQuality,
+		DownlinkSnrDB:      d.DownlinkSnrDB(),
+	})
+}
+
+// Rssi1DBm is the uplink RSSI of antenna 1
+func (d *LinkStatsData) Rssi1DBm() int {
+	return -int(d.UplinkRssiAnt1)
+}
+
+// Rssi2DBm is the uplink RSSI of antenna 2
+func (d *LinkStatsData) Rssi2DBm() int {
+	return -int(d.UplinkRssiAnt2)
+}
+
+func (d *LinkStatsData) DownlinkRssiDBm() int {
+	return -int(d.DownlinkRssi)
+}
+
+func (d *LinkStatsData) UplinkSnrDB() int8 {
+	return d.UplinkSnr
+}
+
+func (d *LinkStatsData) DownlinkSnrDB() int8 {
+	return int8(d.DownlinkSnr)
+}
+
+// ActiveAntenna is 1 or 2
+func (d *LinkStatsData) ActiveAntenna() uint8 {
+	return d.DiversifyActiveAnt + 1
+}
+
+// TxPowerMilliwatts decodes the power enum, unknown values are 0
+func (d *LinkStatsData) TxPowerMilliwatts() int {
+	if int(d.Power) >= len(linkStatsPowerMilliwatts) {
+		return 0
+	}
+	return linkStatsPowerMilliwatts[d.Power]
+}
+
+// PowerIndex returns the power enum closest to milliwatts without going over
+func PowerIndex(milliwatts int) uint8 {
+	index := uint8(0)
+	for i, mw := range linkStatsPowerMilliwatts {
+		if mw <= milliwatts && mw >= linkStatsPowerMilliwatts[index] {
+			index = uint8(i)
+		}
+	}
+	return index
+}
@@ -2,6 +2,7 @@
 package frames
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -10,11 +11,11 @@ const (
 )
 
 type LinkTxData struct {
-	RssiPercent uint8
-	Unknown1    uint8
-	Unknown2    uint8
-	PowerIndex  uint8
-	PacketRate  uint8 //fps/10 (50hz = 0x05 or 5)
+	RssiPercent uint8 `json:"rssi_percent"`
+	Unknown1    uint8 `json:"unknown1"`
+	Unknown2    uint8 `json:"unknown2"`
+	PowerIndex  uint8 `json:"power_index"`
+	PacketRate  uint8 `json:"packet_rate"` //fps/10 (50hz = 0x05 or 5)
 }
 
 func UnmarshalLinkTx(data []byte) (LinkTxData, error) {
@@ -36,8 +37,27 @@ func UnmarshalLinkTx(data []byte) (LinkTxData, error) {
 	return d, nil
 }
 
+func (d *LinkTxData) MarshalLinkTx() []byte {
+	return []byte{
+		d.RssiPercent,
+		d.Unknown1,
+		d.Unknown2,
+		d.PowerIndex,
+		d.PacketRate,
+	}
+}
+
+func (d *LinkTxData) Type() FrameType {
+	return FrameTypeLinkTx
+}
+
+// MarshalPayload implements Frame
+func (d *LinkTxData) MarshalPayload() []byte {
+	return d.MarshalLinkTx()
+}
+
 func (d *LinkTxData) String() string {
-	rate := int(d.PacketRate) * 10
+	rate := d.PacketRateHz()
 	return fmt.Sprintf("RssiPercent: %d%% Unknown1: %d Unknown2: %d PacketRate: %dhz",
 		d.RssiPercent,
 		d.Unknown1,
@@ -45,3 +65,19 @@ func (d *LinkTxData) String() string {
 		rate,
 	)
 }
+
+// MarshalJSON includes the raw fields and the packet rate in Hz
+func (d LinkTxData) MarshalJSON() ([]byte, error) {
+	type raw LinkTxData
+	return json.Marshal(struct {
+		raw
+		PacketRateHz int `json:"packet_rate_hz"`
+	}{
+		raw:          raw(d),
+		PacketRateHz: d.PacketRateHz(),
+	})
+}
+
+func (d *LinkTxData) PacketRateHz() int {
+	return int(d.PacketRate) * 10
+}
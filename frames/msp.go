@@ -0,0 +1,185 @@
+// https://github.com/crsf-wg/crsf/wiki/CRSF_FRAMETYPE_MSP_REQ
+package frames
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+MSP_REQ, MSP_RESP and MSP_WRITE carry an MSP packet split over one or more chunks.
+Every chunk starts with a status byte:
+
+	bits 0-3 sequence number, incremented for every chunk
+	bit 4    start of a new packet
+	bits 5-6 MSP version
+	bit 7    error, set by the flight controller when the command failed
+
+The first chunk of an MSPv1 packet is followed by [size][command], then the payload and a xor checksum.
+*/
+const (
+	MspStatusSeqMask      = 0x0F
+	MspStatusStart        = 0x10
+	MspStatusVersionMask  = 0x60
+	MspStatusVersionShift = 5
+	MspStatusError        = 0x80
+
+	MspVersion1     = 1
+	MspChunkSize    = 57  //packet bytes that fit in one frame after the status byte
+	MspMaxPayload   = 255 //MSPv1 size is a single byte
+	mspHeaderLength = 2   //Size + Command
+)
+
+var (
+	ErrMspVersion  = errors.New("unsupported msp version")
+	ErrMspSequence = errors.New("msp chunk out of sequence")
+	ErrMspChecksum = errors.New("msp packet failed checksum validation")
+	ErrMspTooLong  = errors.New("msp payload too long")
+)
+
+// MspChunkData is one chunk of an MSP packet
+type MspChunkData struct {
+	FrameType FrameType // MSP_REQ, MSP_RESP or MSP_WRITE, UnmarshalMspChunk leaves it to the caller
+	Seq       uint8
+	Start     bool
+	Version   uint8
+	Error     bool
+	Data      []byte
+}
+
+// UnmarshalMspChunk decodes the payload of an extended MSP_REQ, MSP_RESP or MSP_WRITE frame
+func UnmarshalMspChunk(payload []byte) (MspChunkData, error) {
+	d := MspChunkData{}
+	if len(payload) < 1 {
+		return d, ErrFrameLength
+	}
+	status := payload[0]
+	d.Seq = status & MspStatusSeqMask
+	d.Start = status&MspStatusStart != 0
+	d.Version = (status & MspStatusVersionMask) >> MspStatusVersionShift
+	d.Error = status&MspStatusError != 0
+	d.Data = payload[1:]
+	return d, nil
+}
+
+func (d *MspChunkData) Type() FrameType {
+	return d.FrameType
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *MspChunkData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return d.MarshalMspChunk()
+}
+
+func (d *MspChunkData) MarshalMspChunk() []byte {
+	status := d.Seq&MspStatusSeqMask | (d.Version<<MspStatusVersionShift)&MspStatusVersionMask
+	if d.Start {
+		status |= MspStatusStart
+	}
+	if d.Error {
+		status |= MspStatusError
+	}
+
+	payload := make([]byte, 1+len(d.Data))
+	payload[0] = status
+	copy(payload[1:], d.Data)
+	return payload
+}
+
+// MspPacket is a complete MSPv1 request or response
+type MspPacket struct {
+	Command uint8
+	Error   bool
+	Payload []byte
+}
+
+// MspChunks splits the packet into chunks of frameType numbered from seq
+func (p *MspPacket) MspChunks(frameType FrameType, seq uint8) ([]MspChunkData, error) {
+	if len(p.Payload) > MspMaxPayload {
+		return nil, fmt.Errorf("%w: %d bytes", ErrMspTooLong, len(p.Payload))
+	}
+
+	body := make([]byte, 0, mspHeaderLength+len(p.Payload)+1)
+	body = append(body, uint8(len(p.Payload)), p.Command)
+	body = append(body, p.Payload...)
+	body = append(body, MspChecksum(body))
+
+	chunks := make([]MspChunkData, 0, len(body)/MspChunkSize+1)
+	for start := 0; start < len(body); start += MspChunkSize {
+		chunks = append(chunks, MspChunkData{
+			FrameType: frameType,
+			Seq:       seq & MspStatusSeqMask,
+			Start:     start == 0,
+			Version:   MspVersion1,
+			Error:     p.Error && start == 0,
+			Data:      body[start:min(start+MspChunkSize, len(body))],
+		})
+		seq++
+	}
+	return chunks, nil
+}
+
+// MspChecksum is the MSPv1 xor checksum over size, command and payload
+func MspChecksum(data []byte) uint8 {
+	crc := uint8(0)
+	for _, b := range data {
+		crc ^= b
+	}
+	return crc
+}
+
+// MspAssembler joins chunks back into packets
+type MspAssembler struct {
+	started bool
+	seq     uint8
+	isError bool
+	body    []byte
+}
+
+// Add returns the packet once its last chunk has been added.
+// A start chunk always begins a new packet, dropping any partial one.
+// The trailing checksum is verified when the sender included it.
+func (a *MspAssembler) Add(chunk MspChunkData) (MspPacket, bool, error) {
+	if chunk.Start {
+		if chunk.Version != MspVersion1 {
+			a.started = false
+			return MspPacket{}, false, fmt.Errorf("%w: %d", ErrMspVersion, chunk.Version)
+		}
+		a.started = true
+		a.isError = chunk.Error
+		a.body = a.body[:0]
+	} else {
+		if !a.started || chunk.Seq != (a.seq+1)&MspStatusSeqMask {
+			a.started = false
+			return MspPacket{}, false, ErrMspSequence
+		}
+	}
+	a.seq = chunk.Seq
+	a.body = append(a.body, chunk.Data...)
+
+	if len(a.body) < mspHeaderLength {
+		return MspPacket{}, false, nil
+	}
+	size := int(a.body[0])
+	end := mspHeaderLength + size
+	if len(a.body) < end {
+		return MspPacket{}, false, nil
+	}
+	a.started = false
+
+	if len(a.body) > end && MspChecksum(a.body[:end]) != a.body[end] {
+		return MspPacket{}, false, ErrMspChecksum
+	}
+
+	payload := make([]byte, size)
+	copy(payload, a.body[mspHeaderLength:end])
+	return MspPacket{
+		Command: a.body[1],
+		Error:   a.isError,
+		Payload: payload,
+	}, true, nil
+}
+
+func (d *MspChunkData) String() string {
+	return fmt.Sprintf("Seq: %d Start: %t Version: %d Error: %t Data: %v", d.Seq, d.Start, d.Version, d.Error, d.Data)
+}
@@ -0,0 +1,145 @@
+package frames
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// sendChunks passes chunks through their wire encoding, as MSP_RESP frames would carry them
+func sendChunks(t *testing.T, assembler *MspAssembler, chunks []MspChunkData) (MspPacket, bool, error) {
+	t.Helper()
+	var (
+		packet MspPacket
+		done   bool
+		err    error
+	)
+	for i, chunk := range chunks {
+		decoded, unmarshalErr := UnmarshalMspChunk(chunk.MarshalMspChunk())
+		if unmarshalErr != nil {
+			t.Fatal(unmarshalErr)
+		}
+		packet, done, err = assembler.Add(decoded)
+		if err != nil || (done && i != len(chunks)-1) {
+			return packet, done, err
+		}
+	}
+	return packet, done, err
+}
+
+func mspPayload(length int) []byte {
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i] = byte(i * 7)
+	}
+	return payload
+}
+
+func TestMspRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		seq     uint8
+		chunks  int
+	}{
+		{"empty", nil, 0, 1},
+		{"one chunk", mspPayload(MspChunkSize - mspHeaderLength - 1), 3, 1},
+		{"two chunks", mspPayload(MspChunkSize), 5, 2},
+		{"largest", mspPayload(MspMaxPayload), 9, 5},
+		{"sequence wrap", mspPayload(3 * MspChunkSize), 14, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sent := MspPacket{Command: 112, Error: true, Payload: test.payload}
+			chunks, err := sent.MspChunks(FrameTypeMspResp, test.seq)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(chunks) != test.chunks {
+				t.Fatalf("%d chunks want %d", len(chunks), test.chunks)
+			}
+			for i, chunk := range chunks {
+				if want := (test.seq + uint8(i)) & MspStatusSeqMask; chunk.Seq != want {
+					t.Fatalf("chunk %d seq %d want %d", i, chunk.Seq, want)
+				}
+				if chunk.Start != (i == 0) || chunk.Type() != FrameTypeMspResp {
+					t.Fatalf("chunk %d: %s", i, chunk.String())
+				}
+			}
+
+			packet, done, err := sendChunks(t, &MspAssembler{}, chunks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !done {
+				t.Fatal("packet not complete after its last chunk")
+			}
+			if packet.Command != sent.Command || packet.Error != sent.Error || !bytes.Equal(packet.Payload, sent.Payload) {
+				t.Fatalf("packet %+v want %+v", packet, sent)
+			}
+		})
+	}
+}
+
+func TestMspTooLong(t *testing.T) {
+	packet := MspPacket{Command: 1, Payload: mspPayload(MspMaxPayload + 1)}
+	_, err := packet.MspChunks(FrameTypeMspReq, 0)
+	if !errors.Is(err, ErrMspTooLong) {
+		t.Fatalf("expected ErrMspTooLong, got %v", err)
+	}
+}
+
+func TestMspAssemblerRejects(t *testing.T) {
+	packet := MspPacket{Command: 101, Payload: mspPayload(2 * MspChunkSize)}
+	chunks, err := packet.MspChunks(FrameTypeMspResp, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("checksum", func(t *testing.T) {
+		corrupt := append([]MspChunkData(nil), chunks...)
+		last := &corrupt[len(corrupt)-1]
+		last.Data = bytes.Clone(last.Data)
+		last.Data[len(last.Data)-1] ^= 0xFF
+		_, _, err := sendChunks(t, &MspAssembler{}, corrupt)
+		if !errors.Is(err, ErrMspChecksum) {
+			t.Fatalf("expected ErrMspChecksum, got %v", err)
+		}
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		_, _, err := sendChunks(t, &MspAssembler{}, []MspChunkData{chunks[0], chunks[2]})
+		if !errors.Is(err, ErrMspSequence) {
+			t.Fatalf("expected ErrMspSequence, got %v", err)
+		}
+	})
+
+	t.Run("no start", func(t *testing.T) {
+		_, _, err := sendChunks(t, &MspAssembler{}, chunks[1:])
+		if !errors.Is(err, ErrMspSequence) {
+			t.Fatalf("expected ErrMspSequence, got %v", err)
+		}
+	})
+
+	t.Run("version", func(t *testing.T) {
+		start := chunks[0]
+		start.Version = 2
+		_, _, err := sendChunks(t, &MspAssembler{}, []MspChunkData{start})
+		if !errors.Is(err, ErrMspVersion) {
+			t.Fatalf("expected ErrMspVersion, got %v", err)
+		}
+	})
+
+	t.Run("restart drops partial packet", func(t *testing.T) {
+		assembler := &MspAssembler{}
+		_, _, err := sendChunks(t, assembler, chunks[:2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, done, err := sendChunks(t, assembler, chunks)
+		if err != nil || !done || !bytes.Equal(got.Payload, packet.Payload) {
+			t.Fatalf("packet after restart done %t err %v", done, err)
+		}
+	})
+}
@@ -0,0 +1,631 @@
+// https://github.com/crsf-wg/crsf/wiki/CRSF_FRAMETYPE_PARAMETER_SETTINGS_ENTRY
+package frames
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	ParameterHiddenFlag     = 0x80
+	ParameterTypeMask       = 0x7F
+	ParameterChunkSize      = 56 //entry bytes that fit in one PARAMETER_SETTINGS_ENTRY frame
+	ParameterChildrenEnd    = 0xFF
+	ParameterOptionSplitter = ";"
+
+	ParameterSettingsEntryHeaderLength = 2 //Index + Chunks Remaining
+	ParameterReadLength                = 2 //Index + Chunk
+	ParameterWriteMinLength            = 1 //Index
+	ParameterFloatFieldsLength         = 4*4 + 1 + 4
+)
+
+var (
+	ErrParameterNotWritable = errors.New("parameter type can not be written")
+	ErrParameterValue       = errors.New("invalid parameter value")
+)
+
+// ParameterSettingsEntryData is one chunk of a parameter entry
+type ParameterSettingsEntryData struct {
+	Index           uint8
+	ChunksRemaining uint8
+	Data            []byte
+}
+
+// UnmarshalParameterSettingsEntry decodes the payload of an extended PARAMETER_SETTINGS_ENTRY frame
+func UnmarshalParameterSettingsEntry(payload []byte) (ParameterSettingsEntryData, error) {
+	d := ParameterSettingsEntryData{}
+	if len(payload) < ParameterSettingsEntryHeaderLength {
+		return d, ErrFrameLength
+	}
+	d.Index = payload[0]
+	d.ChunksRemaining = payload[1]
+	d.Data = payload[2:]
+	return d, nil
+}
+
+func (d *ParameterSettingsEntryData) MarshalParameterSettingsEntry() []byte {
+	payload := make([]byte, ParameterSettingsEntryHeaderLength+len(d.Data))
+	payload[0] = d.Index
+	payload[1] = d.ChunksRemaining
+	copy(payload[2:], d.Data)
+	return payload
+}
+
+func (d *ParameterSettingsEntryData) Type() FrameType {
+	return FrameTypeParameterSettingsEntry
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *ParameterSettingsEntryData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return d.MarshalParameterSettingsEntry()
+}
+
+// ParameterReadData requests one chunk of a parameter entry
+type ParameterReadData struct {
+	Index uint8
+	Chunk uint8
+}
+
+// UnmarshalParameterRead decodes the payload of an extended PARAMETER_READ frame
+func UnmarshalParameterRead(payload []byte) (ParameterReadData, error) {
+	d := ParameterReadData{}
+	if len(payload) != ParameterReadLength {
+		return d, ErrFrameLength
+	}
+	d.Index = payload[0]
+	d.Chunk = payload[1]
+	return d, nil
+}
+
+func (d *ParameterReadData) MarshalParameterRead() []byte {
+	return []byte{d.Index, d.Chunk}
+}
+
+func (d *ParameterReadData) Type() FrameType {
+	return FrameTypeParameterRead
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *ParameterReadData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return d.MarshalParameterRead()
+}
+
+// ParameterWriteData sets the value of a parameter, the value encoding depends on the parameter type
+type ParameterWriteData struct {
+	Index uint8
+	Value []byte
+}
+
+// UnmarshalParameterWrite decodes the payload of an extended PARAMETER_WRITE frame
+func UnmarshalParameterWrite(payload []byte) (ParameterWriteData, error) {
+	d := ParameterWriteData{}
+	if len(payload) < ParameterWriteMinLength {
+		return d, ErrFrameLength
+	}
+	d.Index = payload[0]
+	d.Value = payload[1:]
+	return d, nil
+}
+
+func (d *ParameterWriteData) MarshalParameterWrite() []byte {
+	payload := make([]byte, 1+len(d.Value))
+	payload[0] = d.Index
+	copy(payload[1:], d.Value)
+	return payload
+}
+
+func (d *ParameterWriteData) Type() FrameType {
+	return FrameTypeParameterWrite
+}
+
+// MarshalExtendedPayload implements ExtendedData
+func (d *ParameterWriteData) MarshalExtendedPayload(destination, origin AddressType) []byte {
+	return d.MarshalParameterWrite()
+}
+
+// ParameterHeader is common to every parameter type
+type ParameterHeader struct {
+	Index  uint8
+	Parent uint8 //index of the parent folder, 0 is the root
+	Type   ParameterType
+	Hidden bool
+	Name   string
+}
+
+func (h *ParameterHeader) Header() *ParameterHeader {
+	return h
+}
+
+// Parameter is a decoded parameter entry, one of the *Parameter types in this package
+type Parameter interface {
+	Header() *ParameterHeader
+	// MarshalValue returns the value as sent in a PARAMETER_WRITE, or nil if the type can not be written
+	MarshalValue() []byte
+	// UnmarshalValue sets the value from a PARAMETER_WRITE
+	UnmarshalValue(value []byte) error
+	String() string
+
+	marshalFields() []byte
+	unmarshalFields(fields []byte) error
+}
+
+// IntParameter covers the Uint8, Int8, Uint16, Int16, Uint32 and Int32 types
+type IntParameter struct {
+	ParameterHeader
+	Value   int64
+	Min     int64
+	Max     int64
+	Default int64
+	Units   string
+}
+
+type FloatParameter struct {
+	ParameterHeader
+	Value        int32 //value * 10^DecimalPoint
+	Min          int32
+	Max          int32
+	Default      int32
+	DecimalPoint uint8
+	Step         int32
+	Units        string
+}
+
+type TextSelectionParameter struct {
+	ParameterHeader
+	Options []string
+	Value   uint8 //index into Options
+	Min     uint8
+	Max     uint8
+	Default uint8
+	Units   string
+}
+
+type StringParameter struct {
+	ParameterHeader
+	Value     string
+	MaxLength uint8 //0 when not sent
+}
+
+type FolderParameter struct {
+	ParameterHeader
+	Children []uint8
+}
+
+type InfoParameter struct {
+	ParameterHeader
+	Value string
+}
+
+type CommandParameter struct {
+	ParameterHeader
+	Status  CommandStatus
+	Timeout uint8 //100ms units
+	Info    string
+}
+
+// NewParameter returns an empty parameter of the given type
+func NewParameter(parameterType ParameterType) (Parameter, error) {
+	header := ParameterHeader{Type: parameterType}
+	switch parameterType {
+	case ParameterTypeUint8, ParameterTypeInt8, ParameterTypeUint16, ParameterTypeInt16, ParameterTypeUint32, ParameterTypeInt32:
+		return &IntParameter{ParameterHeader: header}, nil
+	case ParameterTypeFloat:
+		return &FloatParameter{ParameterHeader: header}, nil
+	case ParameterTypeTextSelection:
+		return &TextSelectionParameter{ParameterHeader: header}, nil
+	case ParameterTypeString:
+		return &StringParameter{ParameterHeader: header}, nil
+	case ParameterTypeFolder:
+		return &FolderParameter{ParameterHeader: header}, nil
+	case ParameterTypeInfo:
+		return &InfoParameter{ParameterHeader: header}, nil
+	case ParameterTypeCommand:
+		return &CommandParameter{ParameterHeader: header}, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type: %s", parameterType.String())
+	}
+}
+
+// UnmarshalParameter decodes a complete parameter entry after all chunks are joined
+func UnmarshalParameter(index uint8, data []byte) (Parameter, error) {
+	if len(data) < 3 { //Parent + Type + Name terminator
+		return nil, ErrFrameLength
+	}
+
+	p, err := NewParameter(ParameterType(data[1] & ParameterTypeMask))
+	if err != nil {
+		return nil, err
+	}
+
+	header := p.Header()
+	header.Index = index
+	header.Parent = data[0]
+	header.Hidden = data[1]&ParameterHiddenFlag != 0
+
+	name, fields, err := splitString(data[2:])
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+
+	err = p.unmarshalFields(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing %s parameter %d: %w", header.Type.String(), index, err)
+	}
+	return p, nil
+}
+
+// MarshalParameter encodes a complete parameter entry, split it into ParameterChunkSize chunks to send it
+func MarshalParameter(p Parameter) []byte {
+	header := p.Header()
+	typeByte := byte(header.Type) & ParameterTypeMask
+	if header.Hidden {
+		typeByte |= ParameterHiddenFlag
+	}
+
+	data := []byte{header.Parent, typeByte}
+	data = appendString(data, header.Name)
+	return append(data, p.marshalFields()...)
+}
+
+// FindParameter returns the first parameter with the given name, or nil
+func FindParameter(params []Parameter, name string) Parameter {
+	for _, p := range params {
+		if p.Header().Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (p *IntParameter) size() int {
+	switch p.Type {
+	case ParameterTypeUint8, ParameterTypeInt8:
+		return 1
+	case ParameterTypeUint16, ParameterTypeInt16:
+		return 2
+	default:
+		return 4
+	}
+}
+
+func (p *IntParameter) signed() bool {
+	return p.Type == ParameterTypeInt8 || p.Type == ParameterTypeInt16 || p.Type == ParameterTypeInt32
+}
+
+func (p *IntParameter) getValue(data []byte) int64 {
+	switch p.size() {
+	case 1:
+		if p.signed() {
+			return int64(int8(data[0]))
+		}
+		return int64(data[0])
+	case 2:
+		v := binary.BigEndian.Uint16(data)
+		if p.signed() {
+			return int64(int16(v))
+		}
+		return int64(v)
+	default:
+		v := binary.BigEndian.Uint32(data)
+		if p.signed() {
+			return int64(int32(v))
+		}
+		return int64(v)
+	}
+}
+
+func (p *IntParameter) putValue(data []byte, value int64) {
+	switch p.size() {
+	case 1:
+		data[0] = byte(value)
+	case 2:
+		binary.BigEndian.PutUint16(data, uint16(value))
+	default:
+		binary.BigEndian.PutUint32(data, uint32(value))
+	}
+}
+
+func (p *IntParameter) MarshalValue() []byte {
+	value := make([]byte, p.size())
+	p.putValue(value, p.Value)
+	return value
+}
+
+func (p *IntParameter) UnmarshalValue(value []byte) error {
+	if len(value) != p.size() {
+		return ErrFrameLength
+	}
+	v := p.getValue(value)
+	if v < p.Min || v > p.Max {
+		return ErrParameterValue
+	}
+	p.Value = v
+	return nil
+}
+
+func (p *IntParameter) marshalFields() []byte {
+	size := p.size()
+	fields := make([]byte, 4*size)
+	p.putValue(fields[0:], p.Value)
+	p.putValue(fields[size:], p.Min)
+	p.putValue(fields[2*size:], p.Max)
+	p.putValue(fields[3*size:], p.Default)
+	return appendString(fields, p.Units)
+}
+
+func (p *IntParameter) unmarshalFields(fields []byte) error {
+	size := p.size()
+	if len(fields) < 4*size {
+		return ErrFrameLength
+	}
+	p.Value = p.getValue(fields[0:])
+	p.Min = p.getValue(fields[size:])
+	p.Max = p.getValue(fields[2*size:])
+	p.Default = p.getValue(fields[3*size:])
+	p.Units, _, _ = splitString(fields[4*size:])
+	return nil
+}
+
+func (p *IntParameter) String() string {
+	return fmt.Sprintf("%s: %d%s (%d-%d)", p.Name, p.Value, p.Units, p.Min, p.Max)
+}
+
+func (p *FloatParameter) Float() float64 {
+	return float64(p.Value) / math.Pow10(int(p.DecimalPoint))
+}
+
+// SetFloat rounds value to DecimalPoint places
+func (p *FloatParameter) SetFloat(value float64) {
+	p.Value = int32(math.Round(value * math.Pow10(int(p.DecimalPoint))))
+}
+
+func (p *FloatParameter) MarshalValue() []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(p.Value))
+}
+
+func (p *FloatParameter) UnmarshalValue(value []byte) error {
+	if len(value) != 4 {
+		return ErrFrameLength
+	}
+	v := int32(binary.BigEndian.Uint32(value))
+	if v < p.Min || v > p.Max {
+		return ErrParameterValue
+	}
+	p.Value = v
+	return nil
+}
+
+func (p *FloatParameter) marshalFields() []byte {
+	fields := make([]byte, 0, ParameterFloatFieldsLength+len(p.Units)+1)
+	fields = binary.BigEndian.AppendUint32(fields, uint32(p.Value))
+	fields = binary.BigEndian.AppendUint32(fields, uint32(p.Min))
+	fields = binary.BigEndian.AppendUint32(fields, uint32(p.Max))
+	fields = binary.BigEndian.AppendUint32(fields, uint32(p.Default))
+	fields = append(fields, p.DecimalPoint)
+	fields = binary.BigEndian.AppendUint32(fields, uint32(p.Step))
+	return appendString(fields, p.Units)
+}
+
+func (p *FloatParameter) unmarshalFields(fields []byte) error {
+	if len(fields) < ParameterFloatFieldsLength {
+		return ErrFrameLength
+	}
+	p.Value = int32(binary.BigEndian.Uint32(fields[0:4]))
+	p.Min = int32(binary.BigEndian.Uint32(fields[4:8]))
+	p.Max = int32(binary.BigEndian.Uint32(fields[8:12]))
+	p.Default = int32(binary.BigEndian.Uint32(fields[12:16]))
+	p.DecimalPoint = fields[16]
+	p.Step = int32(binary.BigEndian.Uint32(fields[17:21]))
+	p.Units, _, _ = splitString(fields[21:])
+	return nil
+}
+
+func (p *FloatParameter) String() string {
+	return fmt.Sprintf("%s: %.*f%s", p.Name, p.DecimalPoint, p.Float(), p.Units)
+}
+
+// Option returns the selected option, or an empty string if the value is out of range
+func (p *TextSelectionParameter) Option() string {
+	if int(p.Value) >= len(p.Options) {
+		return ""
+	}
+	return p.Options[p.Value]
+}
+
+// SetOption selects the option with the given name
+func (p *TextSelectionParameter) SetOption(option string) error {
+	for i := range p.Options {
+		if p.Options[i] == option {
+			p.Value = uint8(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not an option of %s: %w", option, p.Name, ErrParameterValue)
+}
+
+func (p *TextSelectionParameter) MarshalValue() []byte {
+	return []byte{p.Value}
+}
+
+func (p *TextSelectionParameter) UnmarshalValue(value []byte) error {
+	if len(value) != 1 {
+		return ErrFrameLength
+	}
+	if value[0] < p.Min || value[0] > p.Max || int(value[0]) >= len(p.Options) {
+		return ErrParameterValue
+	}
+	p.Value = value[0]
+	return nil
+}
+
+func (p *TextSelectionParameter) marshalFields() []byte {
+	fields := appendString(nil, strings.Join(p.Options, ParameterOptionSplitter))
+	fields = append(fields, p.Value, p.Min, p.Max, p.Default)
+	return appendString(fields, p.Units)
+}
+
+func (p *TextSelectionParameter) unmarshalFields(fields []byte) error {
+	options, rest, err := splitString(fields)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 4 {
+		return ErrFrameLength
+	}
+	p.Options = strings.Split(options, ParameterOptionSplitter)
+	p.Value = rest[0]
+	p.Min = rest[1]
+	p.Max = rest[2]
+	p.Default = rest[3]
+	p.Units, _, _ = splitString(rest[4:])
+	return nil
+}
+
+func (p *TextSelectionParameter) String() string {
+	return fmt.Sprintf("%s: %s%s [%s]", p.Name, p.Option(), p.Units, strings.Join(p.Options, ","))
+}
+
+func (p *StringParameter) MarshalValue() []byte {
+	return appendString(nil, p.Value)
+}
+
+func (p *StringParameter) UnmarshalValue(value []byte) error {
+	v, _, err := splitString(value)
+	if err != nil {
+		v = string(value) //accept a missing terminator
+	}
+	if p.MaxLength > 0 && len(v) > int(p.MaxLength) {
+		return ErrParameterValue
+	}
+	p.Value = v
+	return nil
+}
+
+func (p *StringParameter) marshalFields() []byte {
+	fields := appendString(nil, p.Value)
+	if p.MaxLength > 0 {
+		fields = append(fields, p.MaxLength)
+	}
+	return fields
+}
+
+func (p *StringParameter) unmarshalFields(fields []byte) error {
+	value, rest, err := splitString(fields)
+	if err != nil {
+		return err
+	}
+	p.Value = value
+	if len(rest) > 0 {
+		p.MaxLength = rest[0]
+	}
+	return nil
+}
+
+func (p *StringParameter) String() string {
+	return fmt.Sprintf("%s: %s", p.Name, p.Value)
+}
+
+func (p *FolderParameter) MarshalValue() []byte {
+	return nil
+}
+
+func (p *FolderParameter) UnmarshalValue(value []byte) error {
+	return ErrParameterNotWritable
+}
+
+func (p *FolderParameter) marshalFields() []byte {
+	if len(p.Children) == 0 {
+		return nil
+	}
+	fields := append([]byte{}, p.Children...)
+	return append(fields, ParameterChildrenEnd)
+}
+
+func (p *FolderParameter) unmarshalFields(fields []byte) error {
+	p.Children = nil
+	for _, child := range fields {
+		if child == ParameterChildrenEnd {
+			break
+		}
+		p.Children = append(p.Children, child)
+	}
+	return nil
+}
+
+func (p *FolderParameter) String() string {
+	return fmt.Sprintf("%s: %v", p.Name, p.Children)
+}
+
+func (p *InfoParameter) MarshalValue() []byte {
+	return nil
+}
+
+func (p *InfoParameter) UnmarshalValue(value []byte) error {
+	return ErrParameterNotWritable
+}
+
+func (p *InfoParameter) marshalFields() []byte {
+	return appendString(nil, p.Value)
+}
+
+func (p *InfoParameter) unmarshalFields(fields []byte) error {
+	value, _, err := splitString(fields)
+	if err != nil {
+		return err
+	}
+	p.Value = value
+	return nil
+}
+
+func (p *InfoParameter) String() string {
+	return fmt.Sprintf("%s: %s", p.Name, p.Value)
+}
+
+func (p *CommandParameter) MarshalValue() []byte {
+	return []byte{byte(p.Status)}
+}
+
+func (p *CommandParameter) UnmarshalValue(value []byte) error {
+	if len(value) != 1 {
+		return ErrFrameLength
+	}
+	p.Status = CommandStatus(value[0])
+	return nil
+}
+
+func (p *CommandParameter) marshalFields() []byte {
+	return appendString([]byte{byte(p.Status), p.Timeout}, p.Info)
+}
+
+func (p *CommandParameter) unmarshalFields(fields []byte) error {
+	if len(fields) < 2 {
+		return ErrFrameLength
+	}
+	p.Status = CommandStatus(fields[0])
+	p.Timeout = fields[1]
+	p.Info, _, _ = splitString(fields[2:])
+	return nil
+}
+
+func (p *CommandParameter) String() string {
+	return fmt.Sprintf("%s: %s %s", p.Name, p.Status.String(), p.Info)
+}
+
+// splitString returns the null terminated string at the start of data and the bytes after it
+func splitString(data []byte) (string, []byte, error) {
+	end := bytes.IndexByte(data, 0x00)
+	if end < 0 {
+		return "", nil, ErrFrameLength
+	}
+	return string(data[:end]), data[end+1:], nil
+}
+
+func appendString(data []byte, value string) []byte {
+	data = append(data, value...)
+	return append(data, 0x00)
+}
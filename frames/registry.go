@@ -0,0 +1,85 @@
+package frames
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrUnknownFrameType = errors.New("no decoder registered for frame type")
+
+// FrameDecoder decodes a received frame, [type][payload][crc], into a Frame.
+// data is only valid for the duration of the call, the Frame must copy anything it keeps.
+// The returned Frame is allocated per decoded frame, call the UnmarshalX functions directly where that matters.
+type FrameDecoder func(data []byte) (Frame, error)
+
+// Registry holds the decoder for each frame type, it is safe for concurrent use
+type Registry struct {
+	lock     sync.RWMutex
+	decoders map[FrameType]FrameDecoder
+}
+
+// NewRegistry returns a registry with every standard frame type registered
+func NewRegistry() *Registry {
+	r := &Registry{
+		decoders: make(map[FrameType]FrameDecoder),
+	}
+	r.Register(FrameTypeChannels, decoderFor(UnmarshalChannels))
+	r.Register(FrameTypeChannelSubSet, decoderFor(UnmarshalChannelSubSet))
+	r.Register(FrameTypeGPS, decoderFor(UnmarshalGps))
+	r.Register(FrameTypeVario, decoderFor(UnmarshalVario))
+	r.Register(FrameTypeBatterySensor, decoderFor(UnmarshalBatterySensor))
+	r.Register(FrameTypeBarometer, decoderFor(UnmarshalBarometer))
+	r.Register(FrameTypeLinkStats, decoderFor(UnmarshalLinkStats))
+	r.Register(FrameTypeLinkRx, decoderFor(UnmarshalLinkRx))
+	r.Register(FrameTypeLinkTx, decoderFor(UnmarshalLinkTx))
+	r.Register(FrameTypeAttitude, decoderFor(UnmarshalAttitude))
+	r.Register(FrameTypeFlightMode, decoderFor(UnmarshalFlightMode))
+	return r
+}
+
+// decoderFor adapts an UnmarshalX function to a FrameDecoder returning *XData
+func decoderFor[T any, PT interface {
+	*T
+	Frame
+}](unmarshal func(data []byte) (T, error)) FrameDecoder {
+	return func(data []byte) (Frame, error) {
+		d, err := unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		return PT(&d), nil
+	}
+}
+
+// Register sets the decoder for frameType, replacing any decoder already registered including the standard ones
+func (r *Registry) Register(frameType FrameType, decoder FrameDecoder) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.decoders[frameType] = decoder
+}
+
+func (r *Registry) Unregister(frameType FrameType) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.decoders, frameType)
+}
+
+func (r *Registry) Lookup(frameType FrameType) (FrameDecoder, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	decoder, ok := r.decoders[frameType]
+	return decoder, ok
+}
+
+// Decode decodes data, [type][payload][crc], with the decoder registered for its type
+func (r *Registry) Decode(data []byte) (Frame, error) {
+	if len(data) == 0 {
+		return nil, ErrFrameLength
+	}
+	decoder, ok := r.Lookup(FrameType(data[0]))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFrameType, FrameType(data[0]).String())
+	}
+	return decoder(data)
+}
@@ -27,6 +27,27 @@ func GenerateCrc8Value(data []uint8) uint8 {
 	return crc
 }
 
+// Crc8BA is the crc8 with polynomial 0xBA used inside command frames
+func Crc8BA(crc, a uint8) uint8 {
+	crc = crc ^ a
+	for ii := 0; ii < 8; ii++ {
+		if crc&0x80 != 0 {
+			crc = (crc << 1) ^ 0xBA
+		} else {
+			crc = crc << 1
+		}
+	}
+	return crc & 0xFF
+}
+
+func GenerateCrc8BAValue(data []uint8) uint8 {
+	crc := uint8(0)
+	for _, value := range data {
+		crc = Crc8BA(crc, value)
+	}
+	return crc
+}
+
 func ValidateFrame(frame []uint8) bool {
 	frameSize := len(frame)
 	crc := GenerateCrc8Value(frame[0 : frameSize-1])
@@ -3,7 +3,9 @@ package frames
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"math"
 )
 
 const (
@@ -11,7 +13,14 @@ const (
 )
 
 type VarioData struct {
-	Speed int16 // cm/s (e.g. 1.5m/s sent as 150) Little-Endian
+	Speed int16 `json:"speed"` // cm/s (e.g. 1.5m/s sent as 150) Little-Endian
+}
+
+// NewVarioData builds vario telemetry from a vertical speed in m/s
+func NewVarioData(verticalSpeed float64) VarioData {
+	return VarioData{
+		Speed: int16(math.Round(verticalSpeed * 100)),
+	}
 }
 
 func UnmarshalVario(data []byte) (VarioData, error) {
@@ -28,6 +37,38 @@ func UnmarshalVario(data []byte) (VarioData, error) {
 	return d, nil
 }
 
+func (d *VarioData) MarshalVario() []byte {
+	payload := make([]byte, VarioFrameLength-2)
+	binary.LittleEndian.PutUint16(payload[0:2], uint16(d.Speed))
+	return payload
+}
+
+func (d *VarioData) Type() FrameType {
+	return FrameTypeVario
+}
+
+// MarshalPayload implements Frame
+func (d *VarioData) MarshalPayload() []byte {
+	return d.MarshalVario()
+}
+
 func (d *VarioData) String() string {
 	return fmt.Sprintf("Speed: %dcm/s", d.Speed)
 }
+
+// VerticalSpeed in m/s, positive is climbing
+func (d *VarioData) VerticalSpeed() float64 {
+	return float64(d.Speed) / 100
+}
+
+// MarshalJSON includes the raw fields and the vertical speed in m/s
+func (d VarioData) MarshalJSON() ([]byte, error) {
+	type raw VarioData
+	return json.Marshal(struct {
+		raw
+		VerticalSpeed float64 `json:"vertical_speed_mps"`
+	}{
+		raw:           raw(d),
+		VerticalSpeed: d.VerticalSpeed(),
+	})
+}
@@ -1,13 +1,39 @@
 package crsf
 
 import (
+	"time"
+
 	"github.com/Speshl/go-crsf/frames"
 )
 
 func (c *CRSF) GetData() CRSFData {
 	c.dataLock.RLock()
 	defer c.dataLock.RUnlock()
-	return c.data
+	return c.data.clone()
+}
+
+func (c *CRSF) GetFrameStatus(frameType frames.FrameType) FrameStatus {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.Received[frameType]
+}
+
+func (c *CRSF) Age(frameType frames.FrameType) time.Duration {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.Age(frameType)
+}
+
+func (c *CRSF) IsStale(frameType frames.FrameType, maxAge time.Duration) bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.IsStale(frameType, maxAge)
+}
+
+func (c *CRSF) InFailsafe() bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.failsafe
 }
 
 func (c *CRSF) GetGps() frames.GpsData {
@@ -69,3 +95,11 @@ func (c *CRSF) GetFlightMode() frames.FlightModeData {
 	defer c.dataLock.RUnlock()
 	return c.data.FlightMode
 }
+
+// GetCustomFrame returns the latest frame of a type decoded by a decoder added with RegisterFrame
+func (c *CRSF) GetCustomFrame(frameType frames.FrameType) (frames.Frame, bool) {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	frame, ok := c.data.Custom[frameType]
+	return frame, ok
+}
@@ -0,0 +1,214 @@
+package crsf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var ErrAlreadyRunning = errors.New("crsf is already running")
+
+// Status is the state of the link as seen by Run
+type Status int32
+
+const (
+	StatusClosed     Status = iota // not running
+	StatusConnecting               // transport is being opened or no frame has been decoded yet
+	StatusSynced                   // frames are being decoded
+	StatusLost                     // frames stopped for SyncTimeout or the transport failed and is being reopened
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusClosed:
+		return "closed"
+	case StatusConnecting:
+		return "connecting"
+	case StatusSynced:
+		return "synced"
+	case StatusLost:
+		return "lost"
+	default:
+		return fmt.Sprintf("Status(%d)", int32(s))
+	}
+}
+
+// Status is the current state of the link
+func (c *CRSF) Status() Status {
+	return Status(c.status.Load())
+}
+
+func (c *CRSF) setStatus(status Status) {
+	previous := Status(c.status.Swap(int32(status)))
+	if previous != status {
+		slog.Info("crsf status changed", "path", c.path, "status", status, "previous", previous)
+	}
+}
+
+// Run opens the transport and reads and writes frames until ctx is done or Close is called, which returns nil.
+// With WithReconnect a failed session reopens the serial port with backoff, otherwise the session error is returned.
+// A transport given with WithTransport that reaches io.EOF, such as a finished replay, also returns nil.
+// A CRSF opened from a path can be run again after Run returns, one using WithTransport can only be run once
+// since Run closes the transport.
+func (c *CRSF) Run(ctx context.Context) error {
+	if !c.opts.ReadOnly {
+		err := c.validateTelemetryRates()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.runLock.Lock()
+	if c.runDone != nil {
+		c.runLock.Unlock()
+		return ErrAlreadyRunning
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	c.runCancel = cancel
+	c.runDone = done
+	c.runLock.Unlock()
+
+	defer func() {
+		cancel()
+		c.setStatus(StatusClosed)
+		c.runLock.Lock()
+		c.runCancel = nil
+		c.runDone = nil
+		c.runLock.Unlock()
+		close(done)
+	}()
+
+	backoff := c.opts.ReconnectMinBackoff
+	for {
+		c.setStatus(StatusConnecting)
+		synced, err := c.runSession(runCtx)
+		if runCtx.Err() != nil {
+			slog.Info("crsf closed", "path", c.path)
+			return nil
+		}
+		if c.opts.Transport != nil && errors.Is(err, io.EOF) {
+			slog.Info("crsf transport ended", "path", c.path)
+			return nil
+		}
+		if !c.opts.Reconnect || c.opts.Transport != nil {
+			return err
+		}
+
+		if synced {
+			backoff = c.opts.ReconnectMinBackoff
+		}
+		c.setStatus(StatusLost)
+		slog.Warn("crsf session failed, reconnecting", "path", c.path, "error", err, "backoff", backoff)
+		if sleepContext(runCtx, backoff) != nil {
+			slog.Info("crsf closed", "path", c.path)
+			return nil
+		}
+		backoff = min(backoff*2, c.opts.ReconnectMaxBackoff)
+	}
+}
+
+// Close stops Run, closing the transport and waiting for every goroutine to return.
+// It does nothing when the CRSF is not running and must not be called from a frame handler.
+func (c *CRSF) Close() error {
+	c.runLock.Lock()
+	cancel, done := c.runCancel, c.runDone
+	c.runLock.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	<-done
+	return nil
+}
+
+// runSession opens the transport and runs the reader, parser and writer until one fails or ctx is done.
+// synced reports if any frame was decoded so a reconnect can reset its backoff.
+func (c *CRSF) runSession(ctx context.Context) (bool, error) {
+	transport := c.opts.Transport
+	if transport == nil {
+		port, err := openSerialTransport(c.path, c.opts)
+		if err != nil {
+			return false, fmt.Errorf("failed opening crsf %s: %w", c.path, err)
+		}
+		transport = port
+	}
+
+	c.transportLock.Lock()
+	c.transport = transport
+	c.baudRate = c.opts.BaudRate //a reopened port starts at the configured rate
+	c.fallbackBaudRate = 0
+	c.transportLock.Unlock()
+
+	c.dataLock.Lock()
+	c.failsafe = false //a new session starts from a fresh link, the monitor enters failsafe again if needed
+	c.dataLock.Unlock()
+
+	started := time.Now()
+	crsfGroup, groupCtx := errgroup.WithContext(ctx)
+	c.crsfGroup = crsfGroup
+	c.ctx = groupCtx
+
+	c.readChan = make(chan []byte, readBufferCount)
+	c.readFree = newReadBuffers()
+
+	//closing the transport is what unblocks a reader waiting on the port
+	c.crsfGroup.Go(func() error {
+		<-groupCtx.Done()
+		err := transport.Close()
+		if err != nil {
+			slog.Warn("failed closing transport", "path", c.path, "error", err)
+		}
+		return nil
+	})
+
+	c.crsfGroup.Go(c.startReader)
+	c.crsfGroup.Go(c.startReadParser)
+	c.crsfGroup.Go(func() error {
+		return c.startSyncMonitor(started)
+	})
+
+	if !c.opts.ReadOnly {
+		c.crsfGroup.Go(c.startWriter)
+	}
+
+	if c.opts.FailsafeTimeout > 0 {
+		c.crsfGroup.Go(c.startFailsafeMonitor)
+	}
+
+	if _, ok := transport.(BaudRateSetter); ok && c.opts.BaudRateSyncTimeout > 0 {
+		c.crsfGroup.Go(c.startBaudRateMonitor)
+	}
+
+	err := c.crsfGroup.Wait()
+	synced := c.stats.lastFrame().After(started)
+	if err != nil {
+		return synced, fmt.Errorf("crsf group error: %w", err)
+	}
+	return synced, nil
+}
+
+// startSyncMonitor moves Status between synced and lost as frames start and stop arriving
+func (c *CRSF) startSyncMonitor(started time.Time) error {
+	ticker := time.NewTicker(max(c.opts.SyncTimeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-ticker.C:
+			lastFrame := c.stats.lastFrame()
+			if lastFrame.After(started) && time.Since(lastFrame) < c.opts.SyncTimeout {
+				c.setStatus(StatusSynced)
+			} else if c.Status() == StatusSynced {
+				c.setStatus(StatusLost)
+			}
+		}
+	}
+}
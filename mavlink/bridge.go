@@ -0,0 +1,506 @@
+package mavlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Speshl/go-crsf"
+	"github.com/Speshl/go-crsf/frames"
+	"golang.org/x/sync/errgroup"
+)
+
+// bridgeFrameTypes are the telemetry frames translated to MAVLink
+var bridgeFrameTypes = []frames.FrameType{
+	frames.FrameTypeGPS,
+	frames.FrameTypeAttitude,
+	frames.FrameTypeBatterySensor,
+	frames.FrameTypeBarometer,
+	frames.FrameTypeVario,
+	frames.FrameTypeLinkStats,
+	frames.FrameTypeFlightMode,
+}
+
+/*
+Bridge sends CRSF telemetry as MAVLink to a ground station over UDP
+
+	GpsData           -> GPS_RAW_INT, VFR_HUD
+	AttitudeData      -> ATTITUDE
+	BatterySensorData -> SYS_STATUS, BATTERY_STATUS
+	BarometerData     -> VFR_HUD
+	VarioData         -> VFR_HUD
+	LinkStatsData     -> RADIO_STATUS, SYS_STATUS
+	FlightModeData    -> HEARTBEAT
+
+and, with WithChannelOverrides, applies RC_CHANNELS_OVERRIDE from the ground station to the CRSF channels
+until the ground station releases them or stops sending overrides for OverrideTimeout.
+*/
+type Bridge struct {
+	crsf *crsf.CRSF
+	opts BridgeOptions
+
+	connLock sync.RWMutex // conn is set by Run while LocalAddr may be called from other goroutines
+	conn     *net.UDPConn
+
+	start time.Time
+	seq   uint8
+
+	remoteLock sync.RWMutex
+	remote     *net.UDPAddr
+
+	overrideLock sync.Mutex
+	overridden   map[int]uint16 // channel index to the ticks it had before it was overridden
+	lastOverride time.Time
+
+	// latest telemetry used by the messages built from more than one frame
+	hud        VfrHud
+	sysStatus  SysStatus
+	flightMode string
+}
+
+type BridgeOptions struct {
+	LocalAddress      string        // UDP address to listen on, "" picks a free port
+	RemoteAddress     string        // ground station, packets from other hosts are ignored. "" follows the sender of the last packet
+	SystemID          uint8         // MAVLink system id of the vehicle
+	ComponentID       uint8         // MAVLink component id of the vehicle
+	VehicleType       uint8         // MAV_TYPE sent in HEARTBEAT
+	HeartbeatInterval time.Duration // how often HEARTBEAT and SYS_STATUS are sent
+	ChannelOverrides  bool          // apply RC_CHANNELS_OVERRIDE to the CRSF channels
+	OverrideTimeout   time.Duration // overridden channels are restored when no RC_CHANNELS_OVERRIDE arrives for this long, 0 never restores them
+}
+
+type BridgeOption func(*BridgeOptions)
+
+func WithLocalAddress(address string) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.LocalAddress = address
+	}
+}
+
+func WithRemoteAddress(address string) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.RemoteAddress = address
+	}
+}
+
+func WithSystemID(systemID, componentID uint8) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.SystemID = systemID
+		o.ComponentID = componentID
+	}
+}
+
+func WithVehicleType(vehicleType uint8) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.VehicleType = vehicleType
+	}
+}
+
+func WithHeartbeatInterval(interval time.Duration) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.HeartbeatInterval = interval
+	}
+}
+
+// WithChannelOverrides lets the ground station drive the CRSF channels with RC_CHANNELS_OVERRIDE
+func WithChannelOverrides(enabled bool) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.ChannelOverrides = enabled
+	}
+}
+
+func WithOverrideTimeout(timeout time.Duration) BridgeOption {
+	return func(o *BridgeOptions) {
+		o.OverrideTimeout = timeout
+	}
+}
+
+func GetDefaultBridgeOptions() BridgeOptions {
+	return BridgeOptions{
+		RemoteAddress:     "127.0.0.1:14550",
+		SystemID:          1,
+		ComponentID:       1,
+		VehicleType:       MavTypeGeneric,
+		HeartbeatInterval: time.Second,
+		ChannelOverrides:  false,
+		OverrideTimeout:   time.Second,
+	}
+}
+
+// NewBridge(c, WithRemoteAddress("192.168.1.10:14550"))
+func NewBridge(c *crsf.CRSF, opts ...BridgeOption) *Bridge {
+	options := GetDefaultBridgeOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &Bridge{
+		crsf:       c,
+		opts:       options,
+		overridden: make(map[int]uint16),
+		sysStatus: SysStatus{
+			CurrentBattery:   -1,
+			BatteryRemaining: -1,
+		},
+	}
+}
+
+// Run bridges until ctx is done or the UDP socket fails
+func (b *Bridge) Run(ctx context.Context) error {
+	local, err := net.ResolveUDPAddr("udp", b.opts.LocalAddress)
+	if err != nil {
+		return fmt.Errorf("invalid local address %s: %w", b.opts.LocalAddress, err)
+	}
+	if b.opts.RemoteAddress != "" {
+		remote, err := net.ResolveUDPAddr("udp", b.opts.RemoteAddress)
+		if err != nil {
+			return fmt.Errorf("invalid remote address %s: %w", b.opts.RemoteAddress, err)
+		}
+		b.setRemote(remote)
+	}
+
+	conn, err := net.ListenUDP("udp", local)
+	if err != nil {
+		return fmt.Errorf("failed listening on %s: %w", b.opts.LocalAddress, err)
+	}
+	defer conn.Close()
+	b.connLock.Lock()
+	b.conn = conn
+	b.connLock.Unlock()
+	b.start = time.Now()
+
+	slog.Info("mavlink bridge started", "local", b.conn.LocalAddr(), "remote", b.opts.RemoteAddress)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		return b.startSender(groupCtx)
+	})
+	group.Go(func() error {
+		return b.startReceiver(groupCtx)
+	})
+	if b.opts.ChannelOverrides {
+		group.Go(func() error {
+			return b.startOverrideTimeout(groupCtx)
+		})
+	}
+	group.Go(func() error {
+		<-groupCtx.Done()
+		return b.conn.Close() //unblocks the receiver
+	})
+
+	err = group.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// LocalAddr is the address the bridge is listening on, nil until Run has started
+func (b *Bridge) LocalAddr() net.Addr {
+	b.connLock.RLock()
+	defer b.connLock.RUnlock()
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.LocalAddr()
+}
+
+func (b *Bridge) startSender(ctx context.Context) error {
+	sub := b.crsf.Subscribe(0, bridgeFrameTypes...)
+	defer sub.Unsubscribe()
+
+	ticker := time.NewTicker(b.opts.HeartbeatInterval)
+	defer ticker.Stop()
+
+	b.sendHeartbeat()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			b.sendHeartbeat()
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			b.translate(event)
+		}
+	}
+}
+
+func (b *Bridge) translate(event crsf.Event) {
+	switch d := event.Data.(type) {
+	case frames.GpsData:
+		b.send(b.gpsRawInt(d))
+		b.hud.Groundspeed = float32(d.SpeedKph() / 3.6)
+		b.hud.Heading = int16(uint16(d.Course) / 100)
+		b.hud.Alt = float32(d.AltitudeMeters())
+		b.sysStatus.SensorsPresent |= mavSysStatusSensorGps
+		b.sysStatus.SensorsEnabled |= mavSysStatusSensorGps
+		b.sysStatus.SensorsHealth |= mavSysStatusSensorGps
+		b.send(&b.hud)
+	case frames.AttitudeData:
+		b.send(&Attitude{
+			TimeBootMs: b.timeBootMs(),
+			Roll:       float32(d.RollRadians()),
+			Pitch:      float32(d.PitchRadians()),
+			Yaw:        float32(d.YawRadians()),
+		})
+	case frames.BatterySensorData:
+		b.sysStatus.VoltageBattery = uint16(min(max(d.Volts()*1000, 0), math.MaxUint16-1))
+		b.sysStatus.CurrentBattery = int16(min(d.Amps()*100, math.MaxInt16))
+		b.sysStatus.BatteryRemaining = d.RemainingPercent()
+		b.sysStatus.SensorsPresent |= mavSysStatusSensorBattery
+		b.sysStatus.SensorsEnabled |= mavSysStatusSensorBattery
+		b.sysStatus.SensorsHealth |= mavSysStatusSensorBattery
+		b.send(b.batteryStatus(d))
+	case frames.BarometerData:
+		b.hud.Alt = float32(d.AltitudeMeters())
+		b.hud.Climb = float32(d.VerticalSpeed())
+		b.send(&b.hud)
+	case frames.VarioData:
+		b.hud.Climb = float32(d.VerticalSpeed())
+		b.send(&b.hud)
+	case frames.LinkStatsData:
+		b.sysStatus.DropRateComm = uint16(100-min(d.UplinkQuality, 100)) * 100
+		b.send(radioStatus(d))
+	case frames.FlightModeData:
+		b.flightMode = d.FlightMode
+		b.sendHeartbeat()
+	}
+}
+
+func (b *Bridge) gpsRawInt(d frames.GpsData) *GpsRawInt {
+	fixType := uint8(GpsFixTypeNoFix)
+	if d.SatelliteCount >= 4 {
+		fixType = GpsFixType3D
+	} else if d.SatelliteCount > 0 {
+		fixType = GpsFixType2D
+	}
+
+	return &GpsRawInt{
+		TimeUsec:          uint64(time.Now().UnixMicro()),
+		Lat:               d.Lat,
+		Lon:               d.Long,
+		Alt:               int32(d.AltitudeMeters() * 1000),
+		Eph:               UnknownUint16,
+		Epv:               UnknownUint16,
+		Vel:               uint16(max(d.SpeedKph()/3.6*100, 0)),
+		Cog:               uint16(d.Course),
+		FixType:           fixType,
+		SatellitesVisible: d.SatelliteCount,
+	}
+}
+
+func (b *Bridge) batteryStatus(d frames.BatterySensorData) *BatteryStatus {
+	status := &BatteryStatus{
+		CurrentConsumed:  d.UsedMah(),
+		EnergyConsumed:   -1,
+		Temperature:      UnknownInt16,
+		CurrentBattery:   b.sysStatus.CurrentBattery,
+		BatteryFunction:  MavBatteryFunctionAll,
+		Type:             MavBatteryTypeLipo,
+		BatteryRemaining: d.RemainingPercent(),
+	}
+	for i := range status.Voltages {
+		status.Voltages[i] = UnknownUint16
+	}
+	status.Voltages[0] = b.sysStatus.VoltageBattery //CRSF only has the pack voltage
+	return status
+}
+
+// radioStatus carries link quality in rssi and remrssi, MAVLink has no room for RSSI in dBm
+func radioStatus(d frames.LinkStatsData) *RadioStatus {
+	return &RadioStatus{
+		Rssi:     uint8(uint16(min(d.UplinkQuality, 100)) * 254 / 100),
+		RemRssi:  uint8(uint16(min(d.DownlinkQuality, 100)) * 254 / 100),
+		TxBuf:    100,
+		Noise:    UnknownUint8,
+		RemNoise: UnknownUint8,
+	}
+}
+
+func (b *Bridge) sendHeartbeat() {
+	baseMode := uint8(MavModeFlagCustomModeEnabled)
+	systemStatus := uint8(MavStateStandby)
+	if flightModeArmed(b.flightMode) {
+		baseMode |= MavModeFlagSafetyArmed
+		systemStatus = MavStateActive
+	}
+	if b.crsf.InFailsafe() || strings.Contains(b.flightMode, "!FS!") {
+		systemStatus = MavStateCritical
+	}
+
+	b.send(&Heartbeat{
+		Type:           b.opts.VehicleType,
+		Autopilot:      MavAutopilotGeneric,
+		BaseMode:       baseMode,
+		SystemStatus:   systemStatus,
+		MavlinkVersion: mavlinkVersion,
+	})
+	b.send(&b.sysStatus)
+}
+
+// flightModeArmed follows Betaflight, which adds a * to the flight mode while disarmed
+func flightModeArmed(flightMode string) bool {
+	return flightMode != "" && !strings.HasSuffix(flightMode, "*")
+}
+
+func (b *Bridge) send(msg Message) {
+	remote := b.getRemote()
+	if remote == nil {
+		return
+	}
+
+	packet, err := MarshalPacket(b.seq, b.opts.SystemID, b.opts.ComponentID, msg)
+	if err != nil {
+		slog.Warn("failed marshalling mavlink message", "message_id", msg.MessageID(), "error", err)
+		return
+	}
+	b.seq++
+
+	_, err = b.conn.WriteToUDP(packet, remote)
+	if err != nil {
+		slog.Debug("failed sending mavlink message", "remote", remote, "error", err)
+	}
+}
+
+func (b *Bridge) startReceiver(ctx context.Context) error {
+	buff := make([]byte, 2048)
+	for {
+		n, addr, err := b.conn.ReadFromUDP(buff)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed reading mavlink: %w", err)
+		}
+		if !b.acceptFrom(addr) {
+			continue
+		}
+
+		data := buff[:n]
+		for len(data) > 0 {
+			packet, length, err := UnmarshalPacket(data)
+			if length == 0 {
+				break //not mavlink or truncated, drop the rest of the datagram
+			}
+			data = data[length:]
+			if err != nil {
+				if !errors.Is(err, ErrUnknownMessage) {
+					slog.Debug("failed parsing mavlink packet", "remote", addr, "error", err)
+				}
+				continue
+			}
+			b.handlePacket(packet)
+		}
+	}
+}
+
+func (b *Bridge) handlePacket(packet Packet) {
+	switch packet.MessageID {
+	case MessageIDRcChannelsOverride:
+		if !b.opts.ChannelOverrides {
+			return
+		}
+		override := UnmarshalRcChannelsOverride(packet)
+		if override.TargetSystem != 0 && override.TargetSystem != b.opts.SystemID {
+			return
+		}
+		b.applyOverride(override)
+	}
+}
+
+// acceptFrom reports if a packet from addr should be handled, following the sender when no remote address is configured
+func (b *Bridge) acceptFrom(addr *net.UDPAddr) bool {
+	if b.opts.RemoteAddress == "" {
+		b.setRemote(addr)
+		return true
+	}
+	remote := b.getRemote()
+	return remote != nil && remote.IP.Equal(addr.IP)
+}
+
+func (b *Bridge) applyOverride(override RcChannelsOverride) {
+	b.overrideLock.Lock()
+	defer b.overrideLock.Unlock()
+
+	b.lastOverride = time.Now()
+	for ch := 0; ch < frames.MaxChannels; ch++ {
+		switch {
+		case override.Release(ch):
+			b.releaseChannel(ch)
+		case override.Override(ch):
+			if _, ok := b.overridden[ch]; !ok {
+				channels := b.crsf.GetChannels()
+				b.overridden[ch] = channels.Ticks(ch)
+			}
+			b.crsf.SetChannelMicroseconds(ch, float64(override.Channels[ch]))
+		}
+	}
+}
+
+// releaseChannel restores the value ch had before it was overridden, overrideLock must be held
+func (b *Bridge) releaseChannel(ch int) {
+	ticks, ok := b.overridden[ch]
+	if !ok {
+		return
+	}
+	b.crsf.SetChannel(ch, ticks)
+	delete(b.overridden, ch)
+}
+
+func (b *Bridge) releaseOverrides() {
+	b.overrideLock.Lock()
+	defer b.overrideLock.Unlock()
+	for ch := range b.overridden {
+		b.releaseChannel(ch)
+	}
+}
+
+// startOverrideTimeout hands overridden channels back when the ground station stops sending overrides or the bridge stops
+func (b *Bridge) startOverrideTimeout(ctx context.Context) error {
+	defer b.releaseOverrides()
+	if b.opts.OverrideTimeout <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(max(b.opts.OverrideTimeout/4, 10*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			b.overrideLock.Lock()
+			expired := len(b.overridden) > 0 && time.Since(b.lastOverride) > b.opts.OverrideTimeout
+			b.overrideLock.Unlock()
+			if expired {
+				slog.Warn("mavlink channel overrides timed out, restoring channels", "timeout", b.opts.OverrideTimeout)
+				b.releaseOverrides()
+			}
+		}
+	}
+}
+
+func (b *Bridge) setRemote(addr *net.UDPAddr) {
+	b.remoteLock.Lock()
+	defer b.remoteLock.Unlock()
+	b.remote = addr
+}
+
+func (b *Bridge) getRemote() *net.UDPAddr {
+	b.remoteLock.RLock()
+	defer b.remoteLock.RUnlock()
+	return b.remote
+}
+
+func (b *Bridge) timeBootMs() uint32 {
+	return uint32(time.Since(b.start).Milliseconds())
+}
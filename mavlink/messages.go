@@ -0,0 +1,291 @@
+package mavlink
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const (
+	MessageIDHeartbeat          = 0
+	MessageIDSysStatus          = 1
+	MessageIDGpsRawInt          = 24
+	MessageIDAttitude           = 30
+	MessageIDRcChannelsOverride = 70
+	MessageIDVfrHud             = 74
+	MessageIDRadioStatus        = 109
+	MessageIDBatteryStatus      = 147
+)
+
+// crcExtras are the per message checksum seeds from the MAVLink common dialect
+var crcExtras = map[uint32]uint8{
+	MessageIDHeartbeat:          50,
+	MessageIDSysStatus:          124,
+	MessageIDGpsRawInt:          24,
+	MessageIDAttitude:           39,
+	MessageIDRcChannelsOverride: 124,
+	MessageIDVfrHud:             20,
+	MessageIDRadioStatus:        185,
+	MessageIDBatteryStatus:      154,
+}
+
+// MAVLink enum values used by the bridge
+const (
+	MavTypeGeneric     = 0
+	MavTypeFixedWing   = 1
+	MavTypeQuadrotor   = 2
+	MavTypeGroundRover = 10
+
+	MavAutopilotGeneric = 0
+
+	MavModeFlagCustomModeEnabled = 0x01
+	MavModeFlagSafetyArmed       = 0x80
+
+	MavStateStandby  = 3
+	MavStateActive   = 4
+	MavStateCritical = 5
+
+	GpsFixTypeNoFix = 1
+	GpsFixType2D    = 2
+	GpsFixType3D    = 3
+
+	MavBatteryFunctionAll = 1
+	MavBatteryTypeLipo    = 1
+
+	mavSysStatusSensorBattery = 0x02000000 // MAV_SYS_STATUS_SENSOR_BATTERY
+	mavSysStatusSensorGps     = 0x20       // MAV_SYS_STATUS_SENSOR_GPS
+
+	mavlinkVersion = 3
+)
+
+// Unknown values as defined by the messages that use them
+const (
+	UnknownUint16 = math.MaxUint16
+	UnknownInt16  = math.MaxInt16
+	UnknownUint8  = math.MaxUint8
+)
+
+type Heartbeat struct {
+	CustomMode     uint32
+	Type           uint8
+	Autopilot      uint8
+	BaseMode       uint8
+	SystemStatus   uint8
+	MavlinkVersion uint8
+}
+
+func (m *Heartbeat) MessageID() uint32 { return MessageIDHeartbeat }
+
+func (m *Heartbeat) MarshalPayload() []byte {
+	b := binary.LittleEndian.AppendUint32(nil, m.CustomMode)
+	return append(b, m.Type, m.Autopilot, m.BaseMode, m.SystemStatus, m.MavlinkVersion)
+}
+
+type SysStatus struct {
+	SensorsPresent   uint32
+	SensorsEnabled   uint32
+	SensorsHealth    uint32
+	Load             uint16 // d%
+	VoltageBattery   uint16 // mV
+	CurrentBattery   int16  // cA, -1 unknown
+	DropRateComm     uint16 // c%
+	ErrorsComm       uint16
+	ErrorsCount      [4]uint16
+	BatteryRemaining int8 // %, -1 unknown
+}
+
+func (m *SysStatus) MessageID() uint32 { return MessageIDSysStatus }
+
+func (m *SysStatus) MarshalPayload() []byte {
+	b := make([]byte, 0, 31)
+	b = binary.LittleEndian.AppendUint32(b, m.SensorsPresent)
+	b = binary.LittleEndian.AppendUint32(b, m.SensorsEnabled)
+	b = binary.LittleEndian.AppendUint32(b, m.SensorsHealth)
+	b = binary.LittleEndian.AppendUint16(b, m.Load)
+	b = binary.LittleEndian.AppendUint16(b, m.VoltageBattery)
+	b = binary.LittleEndian.AppendUint16(b, uint16(m.CurrentBattery))
+	b = binary.LittleEndian.AppendUint16(b, m.DropRateComm)
+	b = binary.LittleEndian.AppendUint16(b, m.ErrorsComm)
+	for _, count := range m.ErrorsCount {
+		b = binary.LittleEndian.AppendUint16(b, count)
+	}
+	return append(b, uint8(m.BatteryRemaining))
+}
+
+type GpsRawInt struct {
+	TimeUsec          uint64
+	Lat               int32  // degE7
+	Lon               int32  // degE7
+	Alt               int32  // mm above MSL
+	Eph               uint16 // UnknownUint16 if not known
+	Epv               uint16
+	Vel               uint16 // cm/s
+	Cog               uint16 // cdeg
+	FixType           uint8
+	SatellitesVisible uint8
+}
+
+func (m *GpsRawInt) MessageID() uint32 { return MessageIDGpsRawInt }
+
+func (m *GpsRawInt) MarshalPayload() []byte {
+	b := make([]byte, 0, 30)
+	b = binary.LittleEndian.AppendUint64(b, m.TimeUsec)
+	b = binary.LittleEndian.AppendUint32(b, uint32(m.Lat))
+	b = binary.LittleEndian.AppendUint32(b, uint32(m.Lon))
+	b = binary.LittleEndian.AppendUint32(b, uint32(m.Alt))
+	b = binary.LittleEndian.AppendUint16(b, m.Eph)
+	b = binary.LittleEndian.AppendUint16(b, m.Epv)
+	b = binary.LittleEndian.AppendUint16(b, m.Vel)
+	b = binary.LittleEndian.AppendUint16(b, m.Cog)
+	return append(b, m.FixType, m.SatellitesVisible)
+}
+
+type Attitude struct {
+	TimeBootMs uint32
+	Roll       float32 // rad
+	Pitch      float32
+	Yaw        float32
+	RollSpeed  float32 // rad/s
+	PitchSpeed float32
+	YawSpeed   float32
+}
+
+func (m *Attitude) MessageID() uint32 { return MessageIDAttitude }
+
+func (m *Attitude) MarshalPayload() []byte {
+	b := make([]byte, 0, 28)
+	b = binary.LittleEndian.AppendUint32(b, m.TimeBootMs)
+	for _, v := range []float32{m.Roll, m.Pitch, m.Yaw, m.RollSpeed, m.PitchSpeed, m.YawSpeed} {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	return b
+}
+
+type VfrHud struct {
+	Airspeed    float32 // m/s
+	Groundspeed float32 // m/s
+	Alt         float32 // m
+	Climb       float32 // m/s
+	Heading     int16   // deg 0 to 360
+	Throttle    uint16  // %
+}
+
+func (m *VfrHud) MessageID() uint32 { return MessageIDVfrHud }
+
+func (m *VfrHud) MarshalPayload() []byte {
+	b := make([]byte, 0, 20)
+	for _, v := range []float32{m.Airspeed, m.Groundspeed, m.Alt, m.Climb} {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	b = binary.LittleEndian.AppendUint16(b, uint16(m.Heading))
+	return binary.LittleEndian.AppendUint16(b, m.Throttle)
+}
+
+type RadioStatus struct {
+	RxErrors uint16
+	Fixed    uint16
+	Rssi     uint8 // 0 to 254, UnknownUint8 if not known
+	RemRssi  uint8
+	TxBuf    uint8 // %
+	Noise    uint8
+	RemNoise uint8
+}
+
+func (m *RadioStatus) MessageID() uint32 { return MessageIDRadioStatus }
+
+func (m *RadioStatus) MarshalPayload() []byte {
+	b := make([]byte, 0, 9)
+	b = binary.LittleEndian.AppendUint16(b, m.RxErrors)
+	b = binary.LittleEndian.AppendUint16(b, m.Fixed)
+	return append(b, m.Rssi, m.RemRssi, m.TxBuf, m.Noise, m.RemNoise)
+}
+
+type BatteryStatus struct {
+	CurrentConsumed  int32      // mAh, -1 unknown
+	EnergyConsumed   int32      // hJ, -1 unknown
+	Temperature      int16      // cdegC, UnknownInt16 if not known
+	Voltages         [10]uint16 // mV per cell, UnknownUint16 for unused cells
+	CurrentBattery   int16      // cA, -1 unknown
+	ID               uint8
+	BatteryFunction  uint8
+	Type             uint8
+	BatteryRemaining int8 // %, -1 unknown
+}
+
+func (m *BatteryStatus) MessageID() uint32 { return MessageIDBatteryStatus }
+
+func (m *BatteryStatus) MarshalPayload() []byte {
+	b := make([]byte, 0, 36)
+	b = binary.LittleEndian.AppendUint32(b, uint32(m.CurrentConsumed))
+	b = binary.LittleEndian.AppendUint32(b, uint32(m.EnergyConsumed))
+	b = binary.LittleEndian.AppendUint16(b, uint16(m.Temperature))
+	for _, v := range m.Voltages {
+		b = binary.LittleEndian.AppendUint16(b, v)
+	}
+	b = binary.LittleEndian.AppendUint16(b, uint16(m.CurrentBattery))
+	return append(b, m.ID, m.BatteryFunction, m.Type, uint8(m.BatteryRemaining))
+}
+
+const (
+	rcChannelsOverrideLength = 38
+	RcChannelsOverrideCount  = 18
+)
+
+// RcChannelsOverride values are PWM microseconds. Channels 1 to 8 use UINT16_MAX to leave the channel unchanged
+// and 0 to release it, channels 9 to 18 use 0 or UINT16_MAX to leave it unchanged and UINT16_MAX-1 to release it.
+type RcChannelsOverride struct {
+	Channels        [RcChannelsOverrideCount]uint16
+	TargetSystem    uint8
+	TargetComponent uint8
+}
+
+func (m *RcChannelsOverride) MessageID() uint32 { return MessageIDRcChannelsOverride }
+
+func (m *RcChannelsOverride) MarshalPayload() []byte {
+	b := make([]byte, 0, rcChannelsOverrideLength)
+	for _, v := range m.Channels[:8] {
+		b = binary.LittleEndian.AppendUint16(b, v)
+	}
+	b = append(b, m.TargetSystem, m.TargetComponent)
+	for _, v := range m.Channels[8:] { //extension fields
+		b = binary.LittleEndian.AppendUint16(b, v)
+	}
+	return b
+}
+
+func UnmarshalRcChannelsOverride(p Packet) RcChannelsOverride {
+	data := p.payload(rcChannelsOverrideLength)
+	m := RcChannelsOverride{
+		TargetSystem:    data[16],
+		TargetComponent: data[17],
+	}
+	for i := 0; i < 8; i++ {
+		m.Channels[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	for i := 8; i < RcChannelsOverrideCount; i++ {
+		m.Channels[i] = binary.LittleEndian.Uint16(data[18+(i-8)*2:])
+	}
+	return m
+}
+
+// Override reports if channel index ch (0 is channel 1) carries a value
+func (m *RcChannelsOverride) Override(ch int) bool {
+	if ch < 0 || ch >= RcChannelsOverrideCount {
+		return false
+	}
+	v := m.Channels[ch]
+	if v == 0 || v == math.MaxUint16 {
+		return false
+	}
+	return ch < 8 || v != math.MaxUint16-1
+}
+
+// Release reports if channel index ch (0 is channel 1) is handed back from the ground station
+func (m *RcChannelsOverride) Release(ch int) bool {
+	if ch < 0 || ch >= RcChannelsOverrideCount {
+		return false
+	}
+	if ch < 8 {
+		return m.Channels[ch] == 0
+	}
+	return m.Channels[ch] == math.MaxUint16-1
+}
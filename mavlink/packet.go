@@ -0,0 +1,148 @@
+// Package mavlink bridges CRSF telemetry to MAVLink over UDP so ground control software can use a CRSF link.
+// Only the handful of MAVLink messages the bridge needs are implemented.
+package mavlink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	MagicV1 = 0xFE
+	MagicV2 = 0xFD
+
+	headerLengthV1  = 6  // magic, len, seq, sysid, compid, msgid
+	headerLengthV2  = 10 // magic, len, incompat flags, compat flags, seq, sysid, compid, msgid (3 bytes)
+	checksumLength  = 2
+	signatureLength = 13
+
+	incompatFlagSigned = 0x01
+)
+
+var (
+	ErrPacketLength   = errors.New("incomplete mavlink packet")
+	ErrInvalidMagic   = errors.New("not a mavlink packet")
+	ErrInvalidCRC     = errors.New("mavlink packet failed crc validation")
+	ErrUnknownMessage = errors.New("unknown mavlink message")
+)
+
+// Packet is a MAVLink v1 or v2 packet
+type Packet struct {
+	Seq         uint8
+	SystemID    uint8
+	ComponentID uint8
+	MessageID   uint32
+	Payload     []byte // as sent, MAVLink v2 drops trailing zero bytes
+}
+
+// Message is a MAVLink message the bridge can send
+type Message interface {
+	MessageID() uint32
+	MarshalPayload() []byte
+}
+
+// MarshalPacket encodes msg as a MAVLink v2 packet
+func MarshalPacket(seq, systemID, componentID uint8, msg Message) ([]byte, error) {
+	id := msg.MessageID()
+	crcExtra, ok := crcExtras[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownMessage, id)
+	}
+
+	payload := msg.MarshalPayload()
+	for len(payload) > 1 && payload[len(payload)-1] == 0 { //v2 payload truncation
+		payload = payload[:len(payload)-1]
+	}
+
+	packet := make([]byte, headerLengthV2, headerLengthV2+len(payload)+checksumLength)
+	packet[0] = MagicV2
+	packet[1] = uint8(len(payload))
+	packet[2] = 0 //incompat flags
+	packet[3] = 0 //compat flags
+	packet[4] = seq
+	packet[5] = systemID
+	packet[6] = componentID
+	packet[7] = uint8(id)
+	packet[8] = uint8(id >> 8)
+	packet[9] = uint8(id >> 16)
+	packet = append(packet, payload...)
+
+	crc := crcX25(packet[1:], crcExtra)
+	return binary.LittleEndian.AppendUint16(packet, crc), nil
+}
+
+// UnmarshalPacket decodes the MAVLink v1 or v2 packet at the start of data and returns how many bytes it used.
+// Packets of messages without a known CRC extra return ErrUnknownMessage along with the length so they can be skipped.
+func UnmarshalPacket(data []byte) (Packet, int, error) {
+	p := Packet{}
+	if len(data) < 2 {
+		return p, 0, ErrPacketLength
+	}
+
+	var headerLength, length int
+	switch data[0] {
+	case MagicV1:
+		headerLength = headerLengthV1
+		length = headerLength + int(data[1]) + checksumLength
+		if len(data) < length {
+			return p, 0, ErrPacketLength
+		}
+		p.Seq = data[2]
+		p.SystemID = data[3]
+		p.ComponentID = data[4]
+		p.MessageID = uint32(data[5])
+	case MagicV2:
+		headerLength = headerLengthV2
+		length = headerLength + int(data[1]) + checksumLength
+		if len(data) >= 3 && data[2]&incompatFlagSigned != 0 {
+			length += signatureLength
+		}
+		if len(data) < length {
+			return p, 0, ErrPacketLength
+		}
+		p.Seq = data[4]
+		p.SystemID = data[5]
+		p.ComponentID = data[6]
+		p.MessageID = uint32(data[7]) | uint32(data[8])<<8 | uint32(data[9])<<16
+	default:
+		return p, 0, ErrInvalidMagic
+	}
+
+	payloadEnd := headerLength + int(data[1])
+	p.Payload = data[headerLength:payloadEnd]
+
+	crcExtra, ok := crcExtras[p.MessageID]
+	if !ok {
+		return p, length, fmt.Errorf("%w: %d", ErrUnknownMessage, p.MessageID)
+	}
+	if crcX25(data[1:payloadEnd], crcExtra) != binary.LittleEndian.Uint16(data[payloadEnd:]) {
+		return p, length, ErrInvalidCRC
+	}
+	return p, length, nil
+}
+
+// payload returns the packet payload zero padded to length, undoing MAVLink v2 truncation
+func (p *Packet) payload(length int) []byte {
+	if len(p.Payload) >= length {
+		return p.Payload
+	}
+	padded := make([]byte, length)
+	copy(padded, p.Payload)
+	return padded
+}
+
+// crcX25 is the MAVLink checksum (CRC-16/MCRF4XX) over data followed by the message's crc extra
+func crcX25(data []byte, crcExtra uint8) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc = crcAccumulate(crc, b)
+	}
+	return crcAccumulate(crc, crcExtra)
+}
+
+func crcAccumulate(crc uint16, b uint8) uint16 {
+	tmp := b ^ uint8(crc)
+	tmp ^= tmp << 4
+	return (crc >> 8) ^ (uint16(tmp) << 8) ^ (uint16(tmp) << 3) ^ (uint16(tmp) >> 4)
+}
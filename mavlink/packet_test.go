@@ -0,0 +1,161 @@
+package mavlink
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+)
+
+// The expected packets were encoded independently from the MAVLink common dialect field order and crc extras
+const (
+	heartbeatV2 = "fd090000070101000000000000000203810403dc54"
+	heartbeatV1 = "fe0903ffbe00000000000608c00403ae99" //from a ground station, sysid 255 compid 190
+
+	rcOverrideTruncated = "fd120000c8ffbe460000dc05dc05e803d0070000ffffb004080701016e8b"
+	rcOverrideExtended  = "fd260000c9ffbe460000dc05dc05e803d0070000ffffb00408070101dc0500000000000000000000000000000000feff8b7a"
+)
+
+func TestCrcX25(t *testing.T) {
+	//the CRC-16/MCRF4XX check value, without the crc extra crcX25 adds
+	crc := uint16(0xFFFF)
+	for _, b := range []byte("123456789") {
+		crc = crcAccumulate(crc, b)
+	}
+	if crc != 0x6F91 {
+		t.Fatalf("check value 0x%04X want 0x6F91", crc)
+	}
+}
+
+func TestMarshalHeartbeat(t *testing.T) {
+	msg := &Heartbeat{
+		Type:           MavTypeQuadrotor,
+		Autopilot:      3,
+		BaseMode:       MavModeFlagSafetyArmed | MavModeFlagCustomModeEnabled,
+		SystemStatus:   MavStateActive,
+		MavlinkVersion: mavlinkVersion,
+	}
+	packet, err := MarshalPacket(7, 1, 1, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(packet); got != heartbeatV2 {
+		t.Fatalf("heartbeat %s want %s", got, heartbeatV2)
+	}
+
+	decoded, length, err := UnmarshalPacket(packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != len(packet) || decoded.Seq != 7 || decoded.SystemID != 1 || decoded.ComponentID != 1 || decoded.MessageID != MessageIDHeartbeat {
+		t.Fatalf("decoded %+v length %d", decoded, length)
+	}
+}
+
+func TestUnmarshalPacketV1(t *testing.T) {
+	data, _ := hex.DecodeString(heartbeatV1)
+	packet, length, err := UnmarshalPacket(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != len(data) || packet.Seq != 3 || packet.SystemID != 255 || packet.ComponentID != 190 || packet.MessageID != MessageIDHeartbeat {
+		t.Fatalf("decoded %+v length %d", packet, length)
+	}
+	if packet.Payload[4] != 6 || packet.Payload[5] != 8 {
+		t.Fatalf("payload %x", packet.Payload)
+	}
+}
+
+func TestRcChannelsOverride(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet string
+		seq    uint8
+		ext    [10]uint16
+	}{
+		{"truncated extensions", rcOverrideTruncated, 200, [10]uint16{}},
+		{"extensions", rcOverrideExtended, 201, [10]uint16{0: 1500, 9: math.MaxUint16 - 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &RcChannelsOverride{TargetSystem: 1, TargetComponent: 1}
+			copy(msg.Channels[:], []uint16{1500, 1500, 1000, 2000, 0, math.MaxUint16, 1200, 1800})
+			copy(msg.Channels[8:], test.ext[:])
+
+			packet, err := MarshalPacket(test.seq, 255, 190, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(packet); got != test.packet {
+				t.Fatalf("packet %s want %s", got, test.packet)
+			}
+
+			decoded, _, err := UnmarshalPacket(packet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			override := UnmarshalRcChannelsOverride(decoded)
+			if override != *msg {
+				t.Fatalf("override %+v want %+v", override, *msg)
+			}
+		})
+	}
+}
+
+func TestRcChannelsOverrideValues(t *testing.T) {
+	msg := RcChannelsOverride{}
+	copy(msg.Channels[:], []uint16{1500, 0, math.MaxUint16})
+	copy(msg.Channels[8:], []uint16{1500, 0, math.MaxUint16, math.MaxUint16 - 1})
+
+	tests := []struct {
+		ch       int
+		override bool
+		release  bool
+	}{
+		{0, true, false},
+		{1, false, true},  //0 releases channels 1 to 8
+		{2, false, false}, //UINT16_MAX leaves them unchanged
+		{8, true, false},
+		{9, false, false}, //0 and UINT16_MAX leave channels 9 to 18 unchanged
+		{10, false, false},
+		{11, false, true}, //UINT16_MAX-1 releases them
+		{RcChannelsOverrideCount, false, false},
+	}
+	for _, test := range tests {
+		if got := msg.Override(test.ch); got != test.override {
+			t.Fatalf("channel %d override %t want %t", test.ch, got, test.override)
+		}
+		if got := msg.Release(test.ch); got != test.release {
+			t.Fatalf("channel %d release %t want %t", test.ch, got, test.release)
+		}
+	}
+}
+
+func TestUnmarshalPacketErrors(t *testing.T) {
+	data, _ := hex.DecodeString(heartbeatV2)
+
+	_, length, err := UnmarshalPacket(data[:len(data)-1])
+	if !errors.Is(err, ErrPacketLength) || length != 0 {
+		t.Fatalf("truncated packet: length %d err %v", length, err)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-3] ^= 0xFF
+	_, length, err = UnmarshalPacket(corrupt)
+	if !errors.Is(err, ErrInvalidCRC) || length != len(data) {
+		t.Fatalf("corrupt packet: length %d err %v", length, err)
+	}
+
+	unknown := append([]byte(nil), data...)
+	unknown[7] = 0xFF //message id 255
+	_, length, err = UnmarshalPacket(unknown)
+	if !errors.Is(err, ErrUnknownMessage) || length != len(data) {
+		t.Fatalf("unknown message: length %d err %v", length, err)
+	}
+
+	_, _, err = UnmarshalPacket([]byte{0x55, 0x00})
+	if !errors.Is(err, ErrInvalidMagic) {
+		t.Fatalf("expected ErrInvalidMagic, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package crsf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+var (
+	ErrMspTimeout = errors.New("timed out waiting for msp response")
+	ErrMspFailed  = errors.New("msp command failed")
+)
+
+// MspClient tunnels MSP to a flight controller through MSP_REQ, MSP_RESP and MSP_WRITE frames.
+// Requests are sent one at a time.
+type MspClient struct {
+	crsf        *CRSF
+	destination frames.AddressType
+
+	lock sync.Mutex
+	seq  uint8
+}
+
+// NewMspClient(c, frames.AddressTypeFlightController)
+func NewMspClient(c *CRSF, destination frames.AddressType) *MspClient {
+	return &MspClient{
+		crsf:        c,
+		destination: destination,
+	}
+}
+
+// Request sends an MSPv1 command and returns the response payload.
+// The request is resent if no response arrives within MspTimeout, up to MspRetries times.
+// If the flight controller flags the response as an error ErrMspFailed is returned with the payload.
+func (m *MspClient) Request(ctx context.Context, command uint8, payload []byte) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	responses := make(chan frames.MspPacket, 1)
+	assembler := &frames.MspAssembler{}
+	remove := m.crsf.HandleExtendedFrame(frames.FrameTypeMspResp, m.crsf.opts.Address, func(frame frames.ExtendedFrame) {
+		if frame.Origin != m.destination {
+			return
+		}
+		chunk, err := frames.UnmarshalMspChunk(frame.Payload)
+		if err != nil {
+			return
+		}
+		packet, done, err := assembler.Add(chunk)
+		if err != nil {
+			slog.Debug("dropping msp response", "device", m.destination, "error", err)
+			return
+		}
+		if !done || packet.Command != command {
+			return
+		}
+		select {
+		case responses <- packet:
+		default:
+		}
+	})
+	defer remove()
+
+	for attempt := 0; attempt <= m.crsf.opts.MspRetries; attempt++ {
+		err := m.send(frames.FrameTypeMspReq, command, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		timer := time.NewTimer(m.crsf.opts.MspTimeout)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case packet := <-responses:
+			timer.Stop()
+			if packet.Error {
+				return packet.Payload, fmt.Errorf("%w: command %d", ErrMspFailed, command)
+			}
+			return packet.Payload, nil
+		case <-timer.C:
+			continue
+		}
+	}
+	return nil, fmt.Errorf("%w: command %d", ErrMspTimeout, command)
+}
+
+// Write sends an MSPv1 command in MSP_WRITE frames, which the flight controller does not answer
+func (m *MspClient) Write(command uint8, payload []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.send(frames.FrameTypeMspWrite, command, payload)
+}
+
+func (m *MspClient) send(frameType frames.FrameType, command uint8, payload []byte) error {
+	packet := frames.MspPacket{
+		Command: command,
+		Payload: payload,
+	}
+	chunks, err := packet.MspChunks(frameType, m.seq)
+	if err != nil {
+		return err
+	}
+	m.seq += uint8(len(chunks))
+
+	for _, chunk := range chunks {
+		frame := frames.NewAddressedFrame(m.destination, m.crsf.opts.Address, &chunk)
+		err = m.crsf.SendExtendedFrame(frame.ExtendedFrame())
+		if err != nil {
+			return fmt.Errorf("failed sending msp chunk: %w", err)
+		}
+	}
+	return nil
+}
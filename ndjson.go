@@ -0,0 +1,50 @@
+package crsf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// NDJSONWriter writes events as newline delimited JSON, one decoded frame per line
+type NDJSONWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return &NDJSONWriter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (w *NDJSONWriter) Write(event Event) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	err := w.encoder.Encode(event)
+	if err != nil {
+		return fmt.Errorf("failed writing %s event: %w", event.Type.String(), err)
+	}
+	return nil
+}
+
+// WriteAll writes every event from the subscription until ctx is done or the subscription is closed
+func (w *NDJSONWriter) WriteAll(ctx context.Context, sub *Subscription) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			err := w.Write(event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
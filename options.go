@@ -1,6 +1,10 @@
 package crsf
 
-import "time"
+import (
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
 
 type CRSFOptions struct {
 	BaudRate       int
@@ -8,10 +12,45 @@ type CRSFOptions struct {
 	ReadOnly       bool
 	ReadChannels   bool
 	WriterInterval time.Duration
+	WriteChannels  bool
+	ChannelsFormat frames.FrameType                   // FrameTypeChannels or FrameTypeChannelSubSet
+	ChannelSubSet  ChannelSubSetOptions               // used when ChannelsFormat is FrameTypeChannelSubSet
+	TelemetryRates map[frames.FrameType]time.Duration // telemetry frames the writer sends and how often
+	Transport      Transport                          // nil opens the path as a serial port
+	Recorder       *Recorder                          // when set every raw read and written frame is captured
+	Registry       *frames.Registry                   // decoders for received frames, nil uses frames.NewRegistry
+
+	Address    frames.AddressType     // origin address used for extended frames
+	DeviceInfo *frames.DeviceInfoData // when set DEVICE_PING frames sent to Address are answered with this
+
+	ParameterTimeout time.Duration // how long to wait for a parameter response before retrying
+	ParameterRetries int
+
+	MspTimeout time.Duration // how long to wait for an MSP response before resending the request
+	MspRetries int
+
+	CommandTimeout time.Duration // how long SendCommand waits for an ACK before resending
+	CommandRetries int
+
+	BaudRateSyncTimeout time.Duration // how long after a negotiated baud rate change frames may stop before falling back, 0 disables the fallback
+
+	SyncTimeout         time.Duration // how long frames may stop before Status reports StatusLost
+	Reconnect           bool          // reopen the serial port when a session fails, ignored with a Transport
+	ReconnectMinBackoff time.Duration
+	ReconnectMaxBackoff time.Duration
+
+	FailsafeTimeout  time.Duration // 0 disables failsafe detection
+	FailsafeChannels []uint16      // substituted for the channels while in failsafe, nil keeps the last received values
 }
 
 type Option func(*CRSFOptions)
 
+type ChannelSubSetOptions struct {
+	StartChannel uint8 // index of the first channel sent, 0 is channel 1
+	Count        uint8
+	Resolution   frames.ChannelResolution
+}
+
 func GetDefaultOptions() CRSFOptions {
 	return CRSFOptions{
 		BaudRate:       115200,
@@ -19,6 +58,23 @@ func GetDefaultOptions() CRSFOptions {
 		ReadOnly:       false,
 		ReadChannels:   true,
 		WriterInterval: 5 * time.Millisecond,
+		WriteChannels:  true,
+		ChannelsFormat: frames.FrameTypeChannels,
+		TelemetryRates: map[frames.FrameType]time.Duration{},
+		Address:        frames.AddressTypeRadioTransmitter,
+
+		ParameterTimeout: 500 * time.Millisecond,
+		ParameterRetries: 3,
+		MspTimeout:       time.Second,
+		MspRetries:       2,
+		CommandTimeout:   500 * time.Millisecond,
+		CommandRetries:   3,
+
+		BaudRateSyncTimeout: time.Second,
+
+		SyncTimeout:         500 * time.Millisecond,
+		ReconnectMinBackoff: 250 * time.Millisecond,
+		ReconnectMaxBackoff: 5 * time.Second,
 	}
 }
 
@@ -52,6 +108,139 @@ func WithWriterInterval(interval time.Duration) Option {
 	}
 }
 
+// WithWriteChannels controls whether the writer sends channels frames every WriterInterval
+func WithWriteChannels(writeChannels bool) Option {
+	return func(o *CRSFOptions) {
+		o.WriteChannels = writeChannels
+	}
+}
+
+// WithChannelSubSet makes the writer send channels as subset frames of count channels from startChannel at the given resolution
+func WithChannelSubSet(startChannel, count uint8, resolution frames.ChannelResolution) Option {
+	return func(o *CRSFOptions) {
+		o.ChannelsFormat = frames.FrameTypeChannelSubSet
+		o.ChannelSubSet = ChannelSubSetOptions{
+			StartChannel: startChannel,
+			Count:        count,
+			Resolution:   resolution,
+		}
+	}
+}
+
+// WithTelemetryRate makes the writer send the given telemetry frame type every interval, acting as a flight controller.
+// Rates are checked on the WriterInterval tick so they can not be faster than it.
+// An interval of 0 stops sending that frame type. Run returns ErrUnsupportedTelemetry for a frame type that is not telemetry.
+func WithTelemetryRate(frameType frames.FrameType, interval time.Duration) Option {
+	return func(o *CRSFOptions) {
+		if interval <= 0 {
+			delete(o.TelemetryRates, frameType)
+			return
+		}
+		o.TelemetryRates[frameType] = interval
+	}
+}
+
+// WithAddress sets the origin address used when sending extended frames
+func WithAddress(address frames.AddressType) Option {
+	return func(o *CRSFOptions) {
+		o.Address = address
+	}
+}
+
+// WithDeviceInfo emulates a device at the configured address by answering DEVICE_PING frames
+func WithDeviceInfo(info frames.DeviceInfoData) Option {
+	return func(o *CRSFOptions) {
+		o.DeviceInfo = &info
+	}
+}
+
+// WithParameterTimeout sets how long parameter requests wait for a response and how many times they are resent
+func WithParameterTimeout(timeout time.Duration, retries int) Option {
+	return func(o *CRSFOptions) {
+		o.ParameterTimeout = timeout
+		o.ParameterRetries = retries
+	}
+}
+
+// WithMspTimeout sets how long MSP requests wait for a response and how many times they are resent
+func WithMspTimeout(timeout time.Duration, retries int) Option {
+	return func(o *CRSFOptions) {
+		o.MspTimeout = timeout
+		o.MspRetries = retries
+	}
+}
+
+// WithCommandTimeout sets how long SendCommand waits for an ACK and how many times the command is resent
+func WithCommandTimeout(timeout time.Duration, retries int) Option {
+	return func(o *CRSFOptions) {
+		o.CommandTimeout = timeout
+		o.CommandRetries = retries
+	}
+}
+
+// WithSyncTimeout sets how long frames may stop arriving before Status changes from StatusSynced to StatusLost
+func WithSyncTimeout(timeout time.Duration) Option {
+	return func(o *CRSFOptions) {
+		o.SyncTimeout = timeout
+	}
+}
+
+// WithReconnect reopens the serial port after a failed session, waiting minBackoff before the first attempt
+// and doubling up to maxBackoff while the device is missing
+func WithReconnect(minBackoff, maxBackoff time.Duration) Option {
+	return func(o *CRSFOptions) {
+		o.Reconnect = true
+		o.ReconnectMinBackoff = minBackoff
+		o.ReconnectMaxBackoff = maxBackoff
+	}
+}
+
+// WithBaudRateSyncTimeout sets how long frames may stop arriving after NegotiateBaudRate before the previous baud rate is restored
+func WithBaudRateSyncTimeout(timeout time.Duration) Option {
+	return func(o *CRSFOptions) {
+		o.BaudRateSyncTimeout = timeout
+	}
+}
+
+// WithFailsafe enters failsafe when channels or link stats frames that were arriving stop for longer than timeout.
+// If channels are given they replace the last received channel values while in failsafe,
+// channels missing from the end are set to frames.ChannelsMid.
+func WithFailsafe(timeout time.Duration, channels []uint16) Option {
+	return func(o *CRSFOptions) {
+		o.FailsafeTimeout = timeout
+		o.FailsafeChannels = nil
+		if channels != nil {
+			o.FailsafeChannels = make([]uint16, max(len(channels), frames.MaxChannels))
+			n := copy(o.FailsafeChannels, channels)
+			for i := n; i < len(o.FailsafeChannels); i++ {
+				o.FailsafeChannels[i] = frames.ChannelsMid
+			}
+		}
+	}
+}
+
+// WithTransport runs CRSF over an already opened transport instead of opening the path as a serial port.
+// Run closes the transport when it returns so a blocked Read can not keep it running.
+func WithTransport(transport Transport) Option {
+	return func(o *CRSFOptions) {
+		o.Transport = transport
+	}
+}
+
+// WithRegistry decodes received frames with registry, which can be shared between CRSFs
+func WithRegistry(registry *frames.Registry) Option {
+	return func(o *CRSFOptions) {
+		o.Registry = registry
+	}
+}
+
+// WithRecorder captures everything read from and written to the transport
+func WithRecorder(recorder *Recorder) Option {
+	return func(o *CRSFOptions) {
+		o.Recorder = recorder
+	}
+}
+
 func getOptions(opts []Option) CRSFOptions {
 	options := GetDefaultOptions()
 	for i := range opts {
@@ -0,0 +1,229 @@
+package crsf
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// ParameterWriteFunc is called after a PARAMETER_WRITE has updated the value of p.
+// Returning an error restores the previous value.
+// Command parameters receive the requested status in p.Status and should set it to the resulting status,
+// a status left at a request value (Start, Confirm, Cancel or Poll) is reported back as Ready.
+// It is called without the server locked so it may call back into the server.
+type ParameterWriteFunc func(p frames.Parameter) error
+
+type serverParameter struct {
+	param   frames.Parameter
+	onWrite ParameterWriteFunc
+}
+
+// ParameterServer makes CRSF appear as a configurable device in the radio's device menu.
+// It answers DEVICE_PING, PARAMETER_READ and PARAMETER_WRITE frames sent to its address.
+type ParameterServer struct {
+	crsf    *CRSF
+	address frames.AddressType
+	info    frames.DeviceInfoData
+
+	lock     sync.Mutex
+	params   []serverParameter //index 1 is params[0]
+	removers []func()
+}
+
+// NewParameterServer creates a server for the device at address, use it instead of WithDeviceInfo for the same address.
+// ParameterCount in info is filled in from the added parameters.
+func NewParameterServer(c *CRSF, address frames.AddressType, info frames.DeviceInfoData) *ParameterServer {
+	return &ParameterServer{
+		crsf:    c,
+		address: address,
+		info:    info,
+	}
+}
+
+// Add adds p to the folder at parent (0 for the root) and returns its index.
+// Parameters are served in the order they are added so folders should be added before their children.
+func (s *ParameterServer) Add(parent uint8, p frames.Parameter, onWrite ParameterWriteFunc) uint8 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.params = append(s.params, serverParameter{param: p, onWrite: onWrite})
+	header := p.Header()
+	header.Index = uint8(len(s.params))
+	header.Parent = parent
+	return header.Index
+}
+
+// Parameter returns the parameter at index, or nil.
+// The parameter is shared with the server so it should only be modified from write callbacks.
+func (s *ParameterServer) Parameter(index uint8) frames.Parameter {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if index == 0 || int(index) > len(s.params) {
+		return nil
+	}
+	return s.params[index-1].param
+}
+
+// Start answers requests sent to the server's address
+func (s *ParameterServer) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.removers = append(s.removers,
+		s.crsf.HandleExtendedFrame(frames.FrameTypeDevicePing, s.address, s.handlePing),
+		s.crsf.HandleExtendedFrame(frames.FrameTypeParameterRead, s.address, s.handleRead),
+		s.crsf.HandleExtendedFrame(frames.FrameTypeParameterWrite, s.address, s.handleWrite),
+	)
+}
+
+// Stop stops answering requests
+func (s *ParameterServer) Stop() {
+	s.lock.Lock()
+	removers := s.removers
+	s.removers = nil
+	s.lock.Unlock()
+
+	for _, remove := range removers {
+		remove()
+	}
+}
+
+func (s *ParameterServer) handlePing(frame frames.ExtendedFrame) {
+	s.lock.Lock()
+	info := s.info
+	info.ParameterCount = uint8(len(s.params))
+	s.lock.Unlock()
+
+	s.send(frames.FrameTypeDeviceInfo, frame.Origin, info.MarshalDeviceInfo())
+}
+
+func (s *ParameterServer) handleRead(frame frames.ExtendedFrame) {
+	read, err := frames.UnmarshalParameterRead(frame.Payload)
+	if err != nil {
+		slog.Warn("failed parsing parameter read", "error", err, "origin", frame.Origin)
+		return
+	}
+	s.sendChunk(frame.Origin, read.Index, read.Chunk)
+}
+
+func (s *ParameterServer) handleWrite(frame frames.ExtendedFrame) {
+	write, err := frames.UnmarshalParameterWrite(frame.Payload)
+	if err != nil {
+		slog.Warn("failed parsing parameter write", "error", err, "origin", frame.Origin)
+		return
+	}
+
+	err = s.write(write.Index, write.Value)
+	if err != nil {
+		slog.Warn("failed writing parameter", "error", err, "index", write.Index, "origin", frame.Origin)
+	}
+
+	//reply with the current entry so the radio shows the result
+	s.sendChunk(frame.Origin, write.Index, 0)
+}
+
+func (s *ParameterServer) write(index uint8, value []byte) error {
+	s.lock.Lock()
+	if index == 0 || int(index) > len(s.params) {
+		s.lock.Unlock()
+		return fmt.Errorf("unknown parameter %d", index)
+	}
+	entry := s.params[index-1]
+
+	previous := entry.param.MarshalValue()
+	err := entry.param.UnmarshalValue(value)
+	s.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if entry.onWrite != nil {
+		err = entry.onWrite(entry.param)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err != nil {
+		_ = entry.param.UnmarshalValue(previous)
+		return err
+	}
+
+	if command, ok := entry.param.(*frames.CommandParameter); ok {
+		switch command.Status {
+		case frames.CommandStatusStart, frames.CommandStatusConfirm, frames.CommandStatusCancel, frames.CommandStatusPoll:
+			command.Status = frames.CommandStatusReady
+		}
+	}
+	return nil
+}
+
+func (s *ParameterServer) sendChunk(destination frames.AddressType, index, chunk uint8) {
+	data, ok := s.entry(index)
+	if !ok {
+		slog.Warn("parameter read for unknown index", "index", index, "origin", destination)
+		return
+	}
+
+	chunks := max((len(data)+frames.ParameterChunkSize-1)/frames.ParameterChunkSize, 1)
+	if int(chunk) >= chunks {
+		slog.Warn("parameter read for unknown chunk", "index", index, "chunk", chunk, "origin", destination)
+		return
+	}
+
+	start := int(chunk) * frames.ParameterChunkSize
+	end := min(start+frames.ParameterChunkSize, len(data))
+	entry := frames.ParameterSettingsEntryData{
+		Index:           index,
+		ChunksRemaining: uint8(chunks - 1 - int(chunk)),
+		Data:            data[start:end],
+	}
+	s.send(frames.FrameTypeParameterSettingsEntry, destination, entry.MarshalParameterSettingsEntry())
+}
+
+// entry marshals the parameter at index, index 0 is the root folder
+func (s *ParameterServer) entry(index uint8) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if int(index) > len(s.params) {
+		return nil, false
+	}
+
+	if index == 0 {
+		root := &frames.FolderParameter{ParameterHeader: frames.ParameterHeader{Type: frames.ParameterTypeFolder, Name: s.info.Name}}
+		root.Children = s.children(0)
+		return frames.MarshalParameter(root), true
+	}
+
+	p := s.params[index-1].param
+	if folder, ok := p.(*frames.FolderParameter); ok {
+		copied := *folder //the caller's folder is left as it was added
+		copied.Children = s.children(index)
+		p = &copied
+	}
+	return frames.MarshalParameter(p), true
+}
+
+func (s *ParameterServer) children(parent uint8) []uint8 {
+	children := make([]uint8, 0)
+	for _, entry := range s.params {
+		if header := entry.param.Header(); header.Parent == parent {
+			children = append(children, header.Index)
+		}
+	}
+	return children
+}
+
+func (s *ParameterServer) send(frameType frames.FrameType, destination frames.AddressType, payload []byte) {
+	err := s.crsf.SendExtendedFrame(frames.ExtendedFrame{
+		Type:        frameType,
+		Destination: destination,
+		Origin:      s.address,
+		Payload:     payload,
+	})
+	if err != nil {
+		slog.Warn("failed sending parameter server frame", "error", err, "type", frameType, "destination", destination)
+	}
+}
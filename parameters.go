@@ -0,0 +1,224 @@
+package crsf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+var (
+	ErrParameterTimeout = errors.New("timed out waiting for parameter")
+	ErrUnknownDevice    = errors.New("device did not answer ping")
+)
+
+// DeviceInfo returns the DEVICE_INFO of a device, pinging it if it has not been seen yet
+func (c *CRSF) DeviceInfo(ctx context.Context, device frames.AddressType) (frames.DeviceInfoData, error) {
+	for attempt := 0; attempt <= c.opts.ParameterRetries; attempt++ {
+		if info, ok := c.getDevice(device); ok {
+			return info, nil
+		}
+
+		err := c.Ping(device)
+		if err != nil {
+			return frames.DeviceInfoData{}, err
+		}
+
+		err = sleepContext(ctx, c.opts.ParameterTimeout)
+		if err != nil {
+			return frames.DeviceInfoData{}, err
+		}
+	}
+
+	if info, ok := c.getDevice(device); ok {
+		return info, nil
+	}
+	return frames.DeviceInfoData{}, fmt.Errorf("%s: %w", device.String(), ErrUnknownDevice)
+}
+
+// ReadParameters reads every parameter of a device in index order.
+// Parameter parents describe the tree, a parent of 0 is the root folder.
+func (c *CRSF) ReadParameters(ctx context.Context, device frames.AddressType) ([]frames.Parameter, error) {
+	info, err := c.DeviceInfo(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]frames.Parameter, 0, info.ParameterCount)
+	for index := 1; index <= int(info.ParameterCount); index++ {
+		p, err := c.ReadParameter(ctx, device, uint8(index))
+		if err != nil {
+			return params, err
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// ReadParameter reads a single parameter, joining its chunks
+func (c *CRSF) ReadParameter(ctx context.Context, device frames.AddressType, index uint8) (frames.Parameter, error) {
+	c.parameterLock.Lock()
+	defer c.parameterLock.Unlock()
+	return c.readParameter(ctx, device, index)
+}
+
+// WriteParameter sends the value of p to the device and returns the parameter as read back after the write
+func (c *CRSF) WriteParameter(ctx context.Context, device frames.AddressType, p frames.Parameter) (frames.Parameter, error) {
+	value := p.MarshalValue()
+	if value == nil {
+		return nil, fmt.Errorf("%s: %w", p.Header().Name, frames.ErrParameterNotWritable)
+	}
+
+	c.parameterLock.Lock()
+	defer c.parameterLock.Unlock()
+
+	err := c.writeParameter(device, p.Header().Index, value)
+	if err != nil {
+		return nil, err
+	}
+	return c.readParameter(ctx, device, p.Header().Index)
+}
+
+// ExecuteCommand starts a command parameter and polls it until it finishes.
+// Commands that ask for confirmation are confirmed.
+func (c *CRSF) ExecuteCommand(ctx context.Context, device frames.AddressType, p *frames.CommandParameter) (*frames.CommandParameter, error) {
+	c.parameterLock.Lock()
+	defer c.parameterLock.Unlock()
+
+	status := frames.CommandStatusStart
+	for {
+		err := c.writeParameter(device, p.Index, []byte{byte(status)})
+		if err != nil {
+			return nil, err
+		}
+
+		read, err := c.readParameter(ctx, device, p.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		command, ok := read.(*frames.CommandParameter)
+		if !ok {
+			return nil, fmt.Errorf("parameter %d is not a command", p.Index)
+		}
+
+		switch command.Status {
+		case frames.CommandStatusReady:
+			return command, nil
+		case frames.CommandStatusConfirmationNeeded:
+			status = frames.CommandStatusConfirm
+		default:
+			status = frames.CommandStatusPoll
+			err = sleepContext(ctx, max(time.Duration(command.Timeout)*100*time.Millisecond, 100*time.Millisecond))
+			if err != nil {
+				return command, err
+			}
+		}
+	}
+}
+
+func (c *CRSF) readParameter(ctx context.Context, device frames.AddressType, index uint8) (frames.Parameter, error) {
+	data := make([]byte, 0, frames.ParameterChunkSize)
+	remaining := anyChunksRemaining
+	for chunk := uint8(0); ; chunk++ {
+		entry, err := c.readParameterChunk(ctx, device, index, chunk, remaining)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, entry.Data...)
+		if entry.ChunksRemaining == 0 {
+			break
+		}
+		remaining = int(entry.ChunksRemaining) - 1
+	}
+	return frames.UnmarshalParameter(index, data)
+}
+
+// anyChunksRemaining accepts the first chunk of a parameter, the total number of chunks is not known before it
+const anyChunksRemaining = -1
+
+// readParameterChunk requests a chunk, resending the request on timeout.
+// Entries whose ChunksRemaining is not remaining are answers to an earlier request and are dropped.
+func (c *CRSF) readParameterChunk(ctx context.Context, device frames.AddressType, index, chunk uint8, remaining int) (frames.ParameterSettingsEntryData, error) {
+	entries := make(chan frames.ParameterSettingsEntryData, 1)
+	remove := c.HandleExtendedFrame(frames.FrameTypeParameterSettingsEntry, c.opts.Address, func(frame frames.ExtendedFrame) {
+		if frame.Origin != device {
+			return
+		}
+		entry, err := frames.UnmarshalParameterSettingsEntry(frame.Payload)
+		if err != nil || entry.Index != index {
+			return
+		}
+		if remaining != anyChunksRemaining && int(entry.ChunksRemaining) != remaining {
+			return
+		}
+		entry.Data = slices.Clone(entry.Data)
+		select {
+		case entries <- entry:
+		default:
+		}
+	})
+	defer remove()
+
+	request := frames.ParameterReadData{Index: index, Chunk: chunk}
+	for attempt := 0; attempt <= c.opts.ParameterRetries; attempt++ {
+		err := c.SendExtendedFrame(frames.ExtendedFrame{
+			Type:        frames.FrameTypeParameterRead,
+			Destination: device,
+			Origin:      c.opts.Address,
+			Payload:     request.MarshalParameterRead(),
+		})
+		if err != nil {
+			return frames.ParameterSettingsEntryData{}, fmt.Errorf("failed sending parameter read: %w", err)
+		}
+
+		timer := time.NewTimer(c.opts.ParameterTimeout)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return frames.ParameterSettingsEntryData{}, ctx.Err()
+		case entry := <-entries:
+			timer.Stop()
+			return entry, nil
+		case <-timer.C:
+			continue
+		}
+	}
+	return frames.ParameterSettingsEntryData{}, fmt.Errorf("parameter %d chunk %d from %s: %w", index, chunk, device.String(), ErrParameterTimeout)
+}
+
+func (c *CRSF) writeParameter(device frames.AddressType, index uint8, value []byte) error {
+	write := frames.ParameterWriteData{Index: index, Value: value}
+	err := c.SendExtendedFrame(frames.ExtendedFrame{
+		Type:        frames.FrameTypeParameterWrite,
+		Destination: device,
+		Origin:      c.opts.Address,
+		Payload:     write.MarshalParameterWrite(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed sending parameter write: %w", err)
+	}
+	return nil
+}
+
+func (c *CRSF) getDevice(device frames.AddressType) (frames.DeviceInfoData, bool) {
+	c.devicesLock.RLock()
+	defer c.devicesLock.RUnlock()
+	d, ok := c.devices[device]
+	return d.Info, ok
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
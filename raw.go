@@ -0,0 +1,55 @@
+package crsf
+
+import (
+	"slices"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// RawFrameHandler is called from the read parser for every frame with a valid length, before it is decoded,
+// including types this package does not understand. crcValid is false for frames that failed their crc.
+// The frame is only valid for the duration of the call.
+type RawFrameHandler func(frame frames.RawFrame, crcValid bool)
+
+type rawHandlerEntry struct {
+	handler        RawFrameHandler
+	includeInvalid bool
+}
+
+// HandleRawFrames calls handler with every frame read, frames failing their crc are only passed when includeInvalid is set.
+// The returned function removes the handler.
+func (c *CRSF) HandleRawFrames(includeInvalid bool, handler RawFrameHandler) func() {
+	entry := &rawHandlerEntry{
+		handler:        handler,
+		includeInvalid: includeInvalid,
+	}
+
+	c.rawHandlersLock.Lock()
+	defer c.rawHandlersLock.Unlock()
+	c.rawHandlers = append(c.rawHandlers, entry)
+
+	return func() {
+		c.rawHandlersLock.Lock()
+		defer c.rawHandlersLock.Unlock()
+		c.rawHandlers = slices.DeleteFunc(c.rawHandlers, func(e *rawHandlerEntry) bool {
+			return e == entry
+		})
+	}
+}
+
+func (c *CRSF) rawFrameReceived(frame frames.RawFrame, crcValid bool) {
+	c.rawHandlersLock.RLock()
+	defer c.rawHandlersLock.RUnlock()
+	for _, entry := range c.rawHandlers {
+		if !crcValid && !entry.includeInvalid {
+			continue
+		}
+		entry.handler(frame, crcValid)
+	}
+}
+
+// WriteRaw queues a frame of any type for the writer, address is the sync byte it is sent with
+// and payload is everything between the type and the crc
+func (c *CRSF) WriteRaw(address frames.AddressType, frameType frames.FrameType, payload []byte) error {
+	return c.queueFrame(address, frameType, slices.Clone(payload))
+}
@@ -1,8 +1,10 @@
 package crsf
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 
 	"github.com/Speshl/go-crsf/frames"
@@ -11,171 +13,231 @@ import (
 var (
 	ErrNoPayloadLength = errors.New("payload has no length")
 	ErrPaylodTooLong   = errors.New("payload length too high")
+
+	ErrUnsupportedFrameType = errors.New("unsupported frame type")
+)
+
+const (
+	readBufferSize  = 128
+	readBufferCount = 256 // chunks that can wait for the parser before reads are dropped
 )
 
+// newReadBuffers returns the channel the reader takes empty buffers from, the parser hands them back once decoded
+func newReadBuffers() chan []byte {
+	free := make(chan []byte, readBufferCount)
+	for i := 0; i < readBufferCount; i++ {
+		free <- make([]byte, readBufferSize)
+	}
+	return free
+}
+
 func (c *CRSF) startReader() error {
-	buff := make([]byte, 128)
+	scratch := make([]byte, readBufferSize) //read into when the parser holds every buffer, the data is dropped
 	for {
-		n, err := c.port.Read(buff)
-		if err != nil {
-			return fmt.Errorf("failed reading from %s: %w", c.path, err)
+		if c.ctx.Err() != nil {
+			return c.ctx.Err()
 		}
 
+		buff := scratch
 		select {
-		case <-c.ctx.Done():
-			return c.ctx.Err()
-		case c.readChan <- buff[:n]:
-			continue
+		case buff = <-c.readFree:
 		default:
-			slog.Warn("read channel is full, dropping data", "path", c.path, "data_length", n)
-			continue
 		}
-	}
-}
 
-func (c *CRSF) startReadParser() error {
-	for {
+		n, err := c.transport.Read(buff[:readBufferSize])
 		if c.ctx.Err() != nil {
-			return c.ctx.Err()
+			return c.ctx.Err() //the transport was closed to stop the session
+		}
+		if errors.Is(err, io.EOF) {
+			close(c.readChan) //let the parser finish what was already read
+			return nil
 		}
-
-		addressByte, err := c.getNextByte()
 		if err != nil {
-			slog.Warn("failed to get next byte", "error", err)
-			continue
+			return fmt.Errorf("failed reading from %s: %w", c.path, err)
 		}
 
-		if !frames.AddressType(addressByte).IsValid() {
-			continue
+		if n == 0 {
+			c.releaseReadBuffer(buff, scratch)
+			continue //read timed out
 		}
+		c.stats.update(func(s *Stats) { s.BytesRead += uint64(n) })
 
-		//[sync] [len] [type] [payload] [crc8]
-		//next byte should be the length of the payload
-		lengthByte, err := c.getNextByte()
-		if err != nil {
-			slog.Warn("failed to get next byte", "error", err)
-			continue
+		if c.opts.Recorder != nil {
+			err = c.opts.Recorder.Record(CaptureRead, buff[:n])
+			if err != nil {
+				slog.Warn("failed recording read", "path", c.path, "error", err)
+			}
 		}
 
-		if lengthByte == 0 {
-			return ErrNoPayloadLength
+		if &buff[0] == &scratch[0] {
+			c.stats.update(func(s *Stats) { s.DroppedReads++ })
+			slog.Warn("read channel is full, dropping data", "path", c.path, "data_length", n)
+			continue
 		}
+		c.readChan <- buff[:n] //never blocks, there are only as many buffers as readChan holds
+	}
+}
+
+func (c *CRSF) releaseReadBuffer(buff, scratch []byte) {
+	if &buff[0] == &scratch[0] {
+		return
+	}
+	c.readFree <- buff[:readBufferSize]
+}
 
-		if lengthByte > 62 {
-			return ErrPaylodTooLong
+func (c *CRSF) startReadParser() error {
+	decoder := frames.NewDecoder(&chanReader{ctx: c.ctx, readChan: c.readChan, readFree: c.readFree})
+	resyncs := uint64(0)
+	for {
+		raw, err := decoder.Decode()
+		if decoder.Resyncs() != resyncs {
+			skipped := decoder.Resyncs() - resyncs
+			resyncs = decoder.Resyncs()
+			c.stats.update(func(s *Stats) { s.Resyncs += skipped })
 		}
 
-		//length should be the type + payload + CRC
-		frameBytes, err := c.getNextBytes(int(lengthByte))
+		if c.ctx.Err() != nil {
+			return c.ctx.Err()
+		}
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		if errors.Is(err, frames.ErrInvalidCRC8) {
+			c.stats.frameError(err)
+			c.rawFrameReceived(raw, false)
+			slog.Warn("dropping frame", "error", err, "address", raw.Address, "frame", raw.Frame)
+			continue
+		}
 		if err != nil {
-			slog.Warn("failed to get next bytes", "error", err)
+			//a bad length is most likely noise, the decoder has already moved on to the next sync byte
+			c.stats.frameError(err)
 			continue
 		}
 
-		err = c.applyFrame(frameBytes)
+		c.rawFrameReceived(raw, true)
+		err = c.applyFrame(raw.Frame)
 		if err != nil {
-			slog.Warn("failed to apply frame", "error", err, "address", frames.AddressType(addressByte), "length", lengthByte, "frame", frameBytes)
+			c.stats.frameError(err)
+			slog.Warn("failed to apply frame", "error", err, "address", raw.Address, "length", len(raw.Frame), "frame", raw.Frame)
 			continue
 		}
 	}
 }
 
-func (c *CRSF) applyFrame(frame []byte) error {
-	var err error
-
-	if len(frame) == 0 {
+func (c *CRSF) applyFrame(data []byte) error {
+	if len(data) == 0 {
 		return fmt.Errorf("frame is empty")
 	}
 
 	//first byte of the full payload should be the frame type
-	//slog.Debug("update looking for frame", "length", int(lengthByte), "frame", fullPayload[0], "type", FrameType(fullPayload[0]))
-	switch frames.FrameType(frame[0]) {
-	case frames.FrameTypeChannels:
-		if !c.opts.ReadChannels {
+	frameType := frames.FrameType(data[0])
+	if (frameType == frames.FrameTypeChannels || frameType == frames.FrameTypeChannelSubSet) && !c.opts.ReadChannels {
+		return nil
+	}
+
+	decoder, registered := c.registry.Lookup(frameType)
+	if !registered {
+		if frameType.IsExtended() {
+			err := c.applyExtendedFrame(data)
+			if err != nil {
+				return fmt.Errorf("failed parsing extended frame: %w", err)
+			}
 			return nil
 		}
-		err = c.updateChannels(frame)
-	//telemetry
-	case frames.FrameTypeGPS:
-		err = c.updateGps(frame)
-	case frames.FrameTypeVario:
-		err = c.updateVario(frame)
-	case frames.FrameTypeBatterySensor:
-		err = c.updateBatterySensor(frame)
-	case frames.FrameTypeBarometer:
-		err = c.updateBarometer(frame)
-	case frames.FrameTypeLinkStats:
-		err = c.updateLinkStats(frame)
-	case frames.FrameTypeLinkRx:
-		err = c.updateLinkRx(frame)
-	case frames.FrameTypeLinkTx:
-		err = c.updateLinkTx(frame)
-	case frames.FrameTypeAttitude:
-		err = c.updateAttitude(frame)
-	case frames.FrameTypeFlightMode:
-		err = c.updateFlightMode(frame)
-	default:
-		err = fmt.Errorf("unsupported frame type: %s", frames.FrameType(frame[0]).String())
+		return fmt.Errorf("%w: %s", ErrUnsupportedFrameType, frameType.String())
 	}
 
+	frame, err := decoder(data)
 	if err != nil {
-		err = fmt.Errorf("failed parsing frame: %w", err)
+		return fmt.Errorf("failed parsing frame: %w", err)
 	}
+	c.applyDecodedFrame(frameType, data, frame)
 
-	return err
-}
-
-func (c *CRSF) getNextByte() (byte, error) {
-	if c.readBuffIdx < len(c.readBuff) {
-		b := c.readBuff[c.readBuffIdx]
-		c.readBuffIdx++
-		return b, nil
+	//a registered extended type still reaches the extended handlers
+	if frameType.IsExtended() {
+		extended, err := frames.UnmarshalExtendedFrame(data)
+		if err == nil {
+			c.handleExtendedFrame(extended)
+		}
 	}
+	return nil
+}
 
-	select {
-	case <-c.ctx.Done():
-		return 0, c.ctx.Err()
-	case newBuf, ok := <-c.readChan:
-		if !ok {
-			return 0, fmt.Errorf("read channel closed")
-		}
-		c.readBuff = newBuf
-		if len(c.readBuff) == 0 {
-			c.readBuffIdx = 0
-			return 0, fmt.Errorf("read buffer is empty")
-		}
-		c.readBuffIdx = 1 // Reset index to 1 since showing first byte from next buffer
-		return c.readBuff[0], nil
+// applyDecodedFrame stores a decoded frame in CRSFData and publishes it, frames that are not one of the
+// standard types are kept in CRSFData.Custom
+func (c *CRSF) applyDecodedFrame(frameType frames.FrameType, data []byte, frame frames.Frame) {
+	var published any = frame
+	switch f := frame.(type) {
+	case *frames.ChannelsData:
+		c.updateReceivedChannels(func(d *frames.ChannelsData) {
+			*d = *f
+		})
+		published = *f
+	case *frames.ChannelSubSetData:
+		c.updateReceivedChannels(func(d *frames.ChannelsData) {
+			d.ApplySubSet(*f)
+		})
+		published = *f
+	case *frames.GpsData:
+		c.SetGps(*f)
+		published = *f
+	case *frames.VarioData:
+		c.SetVario(*f)
+		published = *f
+	case *frames.BatterySensorData:
+		c.SetBatterySensor(*f)
+		published = *f
+	case *frames.BarometerData:
+		c.SetBarometer(*f)
+		published = *f
+	case *frames.LinkStatsData:
+		c.SetLinkStats(*f)
+		published = *f
+	case *frames.LinkRxData:
+		c.SetLinkRx(*f)
+		published = *f
+	case *frames.LinkTxData:
+		c.SetLinkTx(*f)
+		published = *f
+	case *frames.AttitudeData:
+		c.SetAttitude(*f)
+		published = *f
+	case *frames.FlightModeData:
+		c.SetFlightMode(*f)
+		published = *f
+	default:
+		c.setCustomFrame(frameType, frame)
 	}
+	c.frameReceived(frameType, data, published)
+}
 
+// chanReader feeds the decoder with the chunks the reader sends on readChan and returns each buffer once it is copied
+type chanReader struct {
+	ctx      context.Context
+	readChan <-chan []byte
+	readFree chan<- []byte
+	current  []byte
+	pending  []byte
 }
 
-func (c *CRSF) getNextBytes(length int) ([]byte, error) {
-	bytes := make([]byte, length)
-	copied := 0
-	for copied < length {
-		remaining := len(c.readBuff) - c.readBuffIdx
-		if remaining == 0 {
-			select {
-			case <-c.ctx.Done():
-				return nil, c.ctx.Err()
-			case newBuff, ok := <-c.readChan:
-				if !ok {
-					return nil, fmt.Errorf("read channel closed")
-				}
-				if len(newBuff) == 0 {
-					return nil, fmt.Errorf("read buffer is empty")
-				}
-				c.readBuff = <-c.readChan
-				c.readBuffIdx = 0
-				remaining = len(c.readBuff)
+func (r *chanReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		if r.current != nil {
+			r.readFree <- r.current[:readBufferSize]
+			r.current = nil
+		}
+		select {
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		case buff, ok := <-r.readChan:
+			if !ok {
+				return 0, io.EOF
 			}
+			r.current = buff
+			r.pending = buff
 		}
-
-		toCopy := min(length-copied, remaining)
-		copy(bytes[copied:], c.readBuff[c.readBuffIdx:c.readBuffIdx+toCopy])
-		c.readBuffIdx += toCopy
-		copied += toCopy
 	}
-	return bytes, nil
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
 }
@@ -0,0 +1,171 @@
+package crsf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// readerTransport replays a fixed stream and then reports io.EOF, like a finished capture
+type readerTransport struct {
+	io.Reader
+}
+
+func (t readerTransport) Write(p []byte) (int, error) { return len(p), nil }
+func (t readerTransport) Close() error                { return nil }
+
+func encodeFrames(t *testing.T, list ...frames.Frame) []byte {
+	t.Helper()
+	var stream []byte
+	for _, frame := range list {
+		var err error
+		stream, err = frames.AppendFrame(stream, frames.AddressTypeFlightController, frame.Type(), frame.MarshalPayload())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return stream
+}
+
+// runPipe runs c over one end of a pipe and returns the other end and the result of Run
+func runPipe(t *testing.T, opts ...Option) (*CRSF, net.Conn, <-chan error) {
+	t.Helper()
+	local, remote := net.Pipe()
+	c := NewCRSF("pipe", append([]Option{WithTransport(local), WithReadOnly(true)}, opts...)...)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(context.Background())
+	}()
+	t.Cleanup(func() {
+		remote.Close()
+		c.Close()
+	})
+	return c, remote, done
+}
+
+func nextEvent(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a frame")
+		return Event{}
+	}
+}
+
+func TestReadDecodesFrames(t *testing.T) {
+	c, remote, _ := runPipe(t)
+	sub := c.Subscribe(16, frames.FrameTypeGPS, frames.FrameTypeBatterySensor)
+	defer sub.Unsubscribe()
+
+	gps := frames.NewGpsData(47.397742, 8.545594, 12.3, 350, 500, 9)
+	battery := frames.NewBatterySensorData(12.6, 3.5, 1234, 77)
+	stream := encodeFrames(t, &gps, &battery)
+
+	//split writes so frames cross read boundaries
+	for len(stream) > 0 {
+		n := min(5, len(stream))
+		_, err := remote.Write(stream[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream = stream[n:]
+	}
+
+	event := nextEvent(t, sub)
+	if got, ok := event.Data.(frames.GpsData); !ok || got != gps {
+		t.Fatalf("gps event %+v want %+v", event.Data, gps)
+	}
+	event = nextEvent(t, sub)
+	if got, ok := event.Data.(frames.BatterySensorData); !ok || got != battery {
+		t.Fatalf("battery event %+v want %+v", event.Data, battery)
+	}
+	if c.GetGps() != gps || c.GetBatterySensor() != battery {
+		t.Fatal("decoded frames were not stored")
+	}
+
+	stats := c.Stats()
+	if stats.FramesDecoded[frames.FrameTypeGPS] != 1 || stats.FramesDecoded[frames.FrameTypeBatterySensor] != 1 || stats.CRCErrors != 0 {
+		t.Fatalf("stats %+v", stats)
+	}
+}
+
+func TestReadCountsCrcErrors(t *testing.T) {
+	c, remote, _ := runPipe(t)
+	sub := c.Subscribe(16, frames.FrameTypeAttitude)
+	defer sub.Unsubscribe()
+
+	attitude := frames.NewAttitudeData(10, -5, 90)
+	corrupt := encodeFrames(t, &attitude)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	_, err := remote.Write(append(corrupt, encodeFrames(t, &attitude)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := nextEvent(t, sub)
+	if got, ok := event.Data.(frames.AttitudeData); !ok || got != attitude {
+		t.Fatalf("attitude event %+v want %+v", event.Data, attitude)
+	}
+	stats := c.Stats()
+	if stats.CRCErrors != 1 || stats.FramesDecoded[frames.FrameTypeAttitude] != 1 {
+		t.Fatalf("crc errors %d frames %d, want 1 and 1", stats.CRCErrors, stats.FramesDecoded[frames.FrameTypeAttitude])
+	}
+}
+
+func TestCloseStopsRun(t *testing.T) {
+	c, remote, done := runPipe(t)
+
+	//wait for the reader to be blocked on the transport
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Status() != StatusConnecting {
+		if time.Now().After(deadline) {
+			t.Fatal("Run did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	err := c.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("Run returned %v after Close", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after Close")
+	}
+	if c.Status() != StatusClosed {
+		t.Fatalf("status %s after Close", c.Status())
+	}
+
+	//Run closed the transport
+	_, err = remote.Write([]byte{0})
+	if !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expected the transport to be closed, write returned %v", err)
+	}
+}
+
+func TestRunTransportEOF(t *testing.T) {
+	flightMode := frames.FlightModeData{FlightMode: "ACRO"}
+	stream := encodeFrames(t, &flightMode)
+	stream = append(stream, 0x00, 0x01) //trailing noise
+	c := NewCRSF("capture", WithTransport(readerTransport{Reader: bytes.NewReader(stream)}), WithReadOnly(true))
+
+	err := c.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned %v at the end of the transport", err)
+	}
+	if got := c.GetFlightMode(); got.FlightMode != "ACRO" {
+		t.Fatalf("flight mode %q", got.FlightMode)
+	}
+}
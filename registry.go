@@ -0,0 +1,15 @@
+package crsf
+
+import "github.com/Speshl/go-crsf/frames"
+
+// RegisterFrame decodes received frames of frameType with decoder instead of dropping them as unsupported.
+// Frames that are not one of the standard types are stored in CRSFData.Custom and published to subscribers,
+// a registered extended frame type is still routed to its extended handlers.
+func (c *CRSF) RegisterFrame(frameType frames.FrameType, decoder frames.FrameDecoder) {
+	c.registry.Register(frameType, decoder)
+}
+
+// Registry is the registry used to decode received frames
+func (c *CRSF) Registry() *frames.Registry {
+	return c.registry
+}
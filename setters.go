@@ -1,22 +1,32 @@
 package crsf
 
 import (
+	"slices"
+	"time"
+
 	"github.com/Speshl/go-crsf/frames"
 )
 
 func (c *CRSF) SetData(data CRSFData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
-	c.data = data
+	c.data = data.clone()
 }
 
-func (c *CRSF) updateChannels(data []byte) error {
-	dataStruct, err := frames.UnmarshalChannels(data)
-	if err != nil {
-		return err
+// frameReceived records the arrival of a decoded frame and publishes it to subscribers
+func (c *CRSF) frameReceived(frameType frames.FrameType, raw []byte, data any) {
+	c.dataLock.Lock()
+	if c.data.Received == nil {
+		c.data.Received = make(map[frames.FrameType]FrameStatus)
 	}
-	c.SetChannels(dataStruct)
-	return nil
+	status := c.data.Received[frameType]
+	status.Time = time.Now()
+	status.Count++
+	c.data.Received[frameType] = status
+	c.dataLock.Unlock()
+
+	c.stats.frameDecoded(frameType, status.Time)
+	c.publish(frameType, raw, data)
 }
 
 func (c *CRSF) SetChannels(data frames.ChannelsData) {
@@ -26,43 +36,79 @@ func (c *CRSF) SetChannels(data frames.ChannelsData) {
 	c.data.Channels = data
 }
 
-func (c *CRSF) updateGps(data []byte) error {
-	dataStruct, err := frames.UnmarshalGps(data)
-	if err != nil {
-		return err
-	}
-	c.SetGps(dataStruct)
-	return nil
+// SetChannel sets the channel at index ch (0 is channel 1) to an 11 bit value
+func (c *CRSF) SetChannel(ch int, ticks uint16) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.SetTicks(ch, ticks)
+	})
 }
 
-func (c *CRSF) SetGps(data frames.GpsData) {
+// SetChannelMicroseconds sets the channel at index ch (0 is channel 1) from a PWM pulse width
+func (c *CRSF) SetChannelMicroseconds(ch int, us float64) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.SetMicroseconds(ch, us)
+	})
+}
+
+// SetChannelNormalized sets the channel at index ch (0 is channel 1) from -1 to 1
+func (c *CRSF) SetChannelNormalized(ch int, value float64) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.SetNormalized(ch, value)
+	})
+}
+
+// SetChannelUnit sets the channel at index ch (0 is channel 1) from 0 to 1
+func (c *CRSF) SetChannelUnit(ch int, value float64) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.SetUnit(ch, value)
+	})
+}
+
+// SetChannelSwitch sets the channel at index ch (0 is channel 1) to a switch position
+func (c *CRSF) SetChannelSwitch(ch int, position, positions int) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.SetSwitchPosition(ch, position, positions)
+	})
+}
+
+// updateChannel applies update to a copy of the channels so values already returned by GetChannels do not change
+func (c *CRSF) updateChannel(update func(d *frames.ChannelsData)) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
-	c.data.Gps = data
+	channels := frames.ChannelsData{Channels: slices.Clone(c.data.Channels.Channels)}
+	update(&channels)
+	c.data.Channels = channels
 }
 
-func (c *CRSF) updateVario(data []byte) error {
-	dataStruct, err := frames.UnmarshalVario(data)
-	if err != nil {
-		return err
+// updateReceivedChannels stores channels decoded from a frame, unless failsafe channels are substituted for them
+func (c *CRSF) updateReceivedChannels(update func(d *frames.ChannelsData)) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	if c.failsafe && c.opts.FailsafeChannels != nil {
+		return
 	}
-	c.SetVario(dataStruct)
-	return nil
+	channels := frames.ChannelsData{Channels: slices.Clone(c.data.Channels.Channels)}
+	update(&channels)
+	c.data.Channels = channels
 }
 
-func (c *CRSF) SetVario(data frames.VarioData) {
+// SetChannelSubSet merges the subset into the current channels
+func (c *CRSF) SetChannelSubSet(data frames.ChannelSubSetData) {
+	c.updateChannel(func(d *frames.ChannelsData) {
+		d.ApplySubSet(data)
+	})
+}
+
+func (c *CRSF) SetGps(data frames.GpsData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
-	c.data.Vario = data
+	c.data.Gps = data
 }
 
-func (c *CRSF) updateBatterySensor(data []byte) error {
-	dataStruct, err := frames.UnmarshalBatterySensor(data)
-	if err != nil {
-		return err
-	}
-	c.SetBatterySensor(dataStruct)
-	return nil
+func (c *CRSF) SetVario(data frames.VarioData) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.data.Vario = data
 }
 
 func (c *CRSF) SetBatterySensor(data frames.BatterySensorData) {
@@ -71,92 +117,48 @@ func (c *CRSF) SetBatterySensor(data frames.BatterySensorData) {
 	c.data.BatterySensor = data
 }
 
-func (c *CRSF) updateBarometer(data []byte) error {
-	dataStruct, err := frames.UnmarshalBarometer(data)
-	if err != nil {
-		return err
-	}
-	c.SetBarometer(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetBarometer(data frames.BarometerData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.Barometer = data
 }
 
-func (c *CRSF) updateLinkStats(data []byte) error {
-	dataStruct, err := frames.UnmarshalLinkStats(data)
-	if err != nil {
-		return err
-	}
-	c.SetLinkStats(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetLinkStats(data frames.LinkStatsData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.LinkStats = data
 }
 
-func (c *CRSF) updateLinkRx(data []byte) error {
-	dataStruct, err := frames.UnmarshalLinkRx(data)
-	if err != nil {
-		return err
-	}
-	c.SetLinkRx(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetLinkRx(data frames.LinkRxData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.LinkRx = data
 }
 
-func (c *CRSF) updateLinkTx(data []byte) error {
-	dataStruct, err := frames.UnmarshalLinkTx(data)
-	if err != nil {
-		return err
-	}
-	c.SetLinkTx(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetLinkTx(data frames.LinkTxData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.LinkTx = data
 }
 
-func (c *CRSF) updateAttitude(data []byte) error {
-	dataStruct, err := frames.UnmarshalAttitude(data)
-	if err != nil {
-		return err
-	}
-	c.SetAttitude(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetAttitude(data frames.AttitudeData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.Attitude = data
 }
 
-func (c *CRSF) updateFlightMode(data []byte) error {
-	dataStruct, err := frames.UnmarshalFlightMode(data)
-	if err != nil {
-		return err
-	}
-	c.SetFlightMode(dataStruct)
-	return nil
-}
-
 func (c *CRSF) SetFlightMode(data frames.FlightModeData) {
 	c.dataLock.Lock()
 	defer c.dataLock.Unlock()
 	c.data.FlightMode = data
 }
+
+// setCustomFrame stores a frame decoded by a registered decoder that is not one of the standard frames
+func (c *CRSF) setCustomFrame(frameType frames.FrameType, frame frames.Frame) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	if c.data.Custom == nil {
+		c.data.Custom = make(map[frames.FrameType]frames.Frame)
+	}
+	c.data.Custom[frameType] = frame
+}
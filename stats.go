@@ -0,0 +1,142 @@
+package crsf
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/Speshl/go-crsf/frames"
+)
+
+// statsRateWindow is how long frames are counted before the measured frame rate is updated
+const statsRateWindow = time.Second
+
+// Stats is a snapshot of the parser and link counters since the CRSF was created
+type Stats struct {
+	BytesRead     uint64                      `json:"bytes_read"`
+	FramesDecoded map[frames.FrameType]uint64 `json:"frames_decoded"`
+	CRCErrors     uint64                      `json:"crc_errors"`
+	LengthErrors  uint64                      `json:"length_errors"`
+	UnknownTypes  uint64                      `json:"unknown_types"`
+	Resyncs       uint64                      `json:"resyncs"`       // times bytes were skipped looking for a sync byte
+	DroppedReads  uint64                      `json:"dropped_reads"` // read chunks dropped because the parser fell behind
+	FramesWritten uint64                      `json:"frames_written"`
+	WriteErrors   uint64                      `json:"write_errors"`
+
+	FrameRate map[frames.FrameType]float64 `json:"frame_rate"` // frames per second measured over the last second
+}
+
+// TotalFramesDecoded is the sum of FramesDecoded over all frame types
+func (s Stats) TotalFramesDecoded() uint64 {
+	total := uint64(0)
+	for _, count := range s.FramesDecoded {
+		total += count
+	}
+	return total
+}
+
+type rateWindow struct {
+	start time.Time
+	count uint64
+	rate  float64
+}
+
+func (w *rateWindow) add(now time.Time) {
+	if w.start.IsZero() {
+		w.start = now
+	}
+	w.count++
+
+	elapsed := now.Sub(w.start)
+	if elapsed >= statsRateWindow {
+		w.rate = float64(w.count) / elapsed.Seconds()
+		w.start = now
+		w.count = 0
+	}
+}
+
+// current lets the rate decay when frames stop arriving instead of holding the last full window
+func (w *rateWindow) current(now time.Time) float64 {
+	elapsed := now.Sub(w.start)
+	if elapsed >= statsRateWindow {
+		return float64(w.count) / elapsed.Seconds()
+	}
+	return w.rate
+}
+
+type linkStats struct {
+	lock        sync.Mutex
+	stats       Stats
+	rates       map[frames.FrameType]*rateWindow
+	lastDecoded time.Time
+}
+
+func newLinkStats() *linkStats {
+	return &linkStats{
+		stats: Stats{
+			FramesDecoded: make(map[frames.FrameType]uint64),
+		},
+		rates: make(map[frames.FrameType]*rateWindow),
+	}
+}
+
+func (s *linkStats) update(f func(*Stats)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f(&s.stats)
+}
+
+func (s *linkStats) frameDecoded(frameType frames.FrameType, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stats.FramesDecoded[frameType]++
+	s.lastDecoded = now
+
+	window, ok := s.rates[frameType]
+	if !ok {
+		window = &rateWindow{}
+		s.rates[frameType] = window
+	}
+	window.add(now)
+}
+
+// frameError counts a frame the parser could not apply
+func (s *linkStats) frameError(err error) {
+	s.update(func(stats *Stats) {
+		switch {
+		case errors.Is(err, frames.ErrInvalidCRC8):
+			stats.CRCErrors++
+		case errors.Is(err, frames.ErrFrameLength), errors.Is(err, ErrNoPayloadLength), errors.Is(err, ErrPaylodTooLong):
+			stats.LengthErrors++
+		case errors.Is(err, ErrUnsupportedFrameType):
+			stats.UnknownTypes++
+		}
+	})
+}
+
+// lastFrame is when any frame was last decoded
+func (s *linkStats) lastFrame() time.Time {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lastDecoded
+}
+
+func (s *linkStats) snapshot(now time.Time) Stats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.stats
+	stats.FramesDecoded = maps.Clone(s.stats.FramesDecoded)
+	stats.FrameRate = make(map[frames.FrameType]float64, len(s.rates))
+	for frameType, window := range s.rates {
+		stats.FrameRate[frameType] = window.current(now)
+	}
+	return stats
+}
+
+// Stats returns the parser and link counters, use it to tell a noisy UART (CRC, length and resync counts)
+// from a link that is simply not sending (low frame rates)
+func (c *CRSF) Stats() Stats {
+	return c.stats.snapshot(time.Now())
+}
@@ -0,0 +1,42 @@
+package crsf
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/albenik/go-serial/v2"
+)
+
+// Transport is the byte stream CRSF frames are read from and written to.
+// Anything that can read, write and close works: a serial port, a net.Conn, a pipe or an in-memory buffer.
+type Transport interface {
+	io.ReadWriteCloser
+}
+
+// BaudRateSetter is implemented by transports that can change their line speed after being opened.
+type BaudRateSetter interface {
+	SetBaudRate(baud int) error
+}
+
+// serialTransport is the default Transport, backed by an albenik serial port
+type serialTransport struct {
+	*serial.Port
+}
+
+func openSerialTransport(path string, opts CRSFOptions) (*serialTransport, error) {
+	port, err := serial.Open(path,
+		serial.WithBaudrate(opts.BaudRate),
+		serial.WithDataBits(8),
+		serial.WithParity(serial.NoParity),
+		serial.WithStopBits(serial.OneStopBit),
+		serial.WithReadTimeout(opts.ReadTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening serial port %s: %w", path, err)
+	}
+	return &serialTransport{Port: port}, nil
+}
+
+func (t *serialTransport) SetBaudRate(baud int) error {
+	return t.Reconfigure(serial.WithBaudrate(baud))
+}
@@ -1,42 +1,173 @@
 package crsf
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/Speshl/go-crsf/frames"
 )
 
+var ErrUnsupportedTelemetry = errors.New("frame type can not be sent as telemetry")
+
+// telemetryFrameTypes can be sent by the writer with WithTelemetryRate
+var telemetryFrameTypes = []frames.FrameType{
+	frames.FrameTypeGPS,
+	frames.FrameTypeVario,
+	frames.FrameTypeBatterySensor,
+	frames.FrameTypeBarometer,
+	frames.FrameTypeLinkStats,
+	frames.FrameTypeLinkRx,
+	frames.FrameTypeLinkTx,
+	frames.FrameTypeAttitude,
+	frames.FrameTypeFlightMode,
+}
+
+// validateTelemetryRates rejects telemetry rates the writer could not send, before a session is started
+func (c *CRSF) validateTelemetryRates() error {
+	for frameType := range c.opts.TelemetryRates {
+		if !slices.Contains(telemetryFrameTypes, frameType) {
+			return fmt.Errorf("%w: %s", ErrUnsupportedTelemetry, frameType.String())
+		}
+	}
+	return nil
+}
+
+// queuedFrame is a one off frame waiting to be sent by the writer
+type queuedFrame struct {
+	address   frames.AddressType
+	frameType frames.FrameType
+	payload   []byte
+}
+
+func (c *CRSF) queueFrame(address frames.AddressType, frameType frames.FrameType, payload []byte) error {
+	if c.opts.ReadOnly {
+		return ErrReadOnly
+	}
+	if len(payload) > frames.MaxPayloadLength {
+		return fmt.Errorf("%w: payload of %d bytes is over %d", frames.ErrFrameLength, len(payload), frames.MaxPayloadLength)
+	}
+
+	select {
+	case c.writeQueue <- queuedFrame{address: address, frameType: frameType, payload: payload}:
+		return nil
+	default:
+		return ErrWriteQueueFull
+	}
+}
+
 func (c *CRSF) startWriter() error {
 	ticker := time.NewTicker(c.opts.WriterInterval)
 	defer ticker.Stop()
 
+	lastSent := make(map[frames.FrameType]time.Time, len(c.opts.TelemetryRates))
+	buff := make([]byte, 0, frames.MaxFrameLength)
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			return c.ctx.Err()
-		case <-ticker.C:
-			c.dataLock.RLock()
-			channelsData := c.data.Channels
-			c.dataLock.RUnlock()
-
-			fullFrame, err := c.buildFrame(frames.FrameTypeChannels, channelsData.MarshalChannels())
+		case queued := <-c.writeQueue:
+			err := c.writeFrame(buff, queued.address, queued.frameType, queued.payload)
 			if err != nil {
-				return fmt.Errorf("failed building frame: %w", err)
+				return err
+			}
+		case now := <-ticker.C:
+			if c.opts.WriteChannels {
+				payload := c.channelsPayload()
+				if len(payload) > 0 { //nothing to send until channels are set
+					err := c.writeFrame(buff, frames.AddressTypeTransmitter, c.opts.ChannelsFormat, payload)
+					if err != nil {
+						return err
+					}
+				}
 			}
 
-			_, err = c.port.Write(fullFrame)
-			if err != nil {
-				return fmt.Errorf("failed writing to %s: %w", c.path, err)
+			for frameType, interval := range c.opts.TelemetryRates {
+				if now.Sub(lastSent[frameType]) < interval {
+					continue
+				}
+
+				payload, err := c.telemetryPayload(frameType)
+				if err != nil {
+					return err
+				}
+
+				err = c.writeFrame(buff, frames.AddressTypeFlightController, frameType, payload)
+				if err != nil {
+					return err
+				}
+				lastSent[frameType] = now
 			}
 		}
 	}
 }
 
-func (c *CRSF) buildFrame(frameType frames.FrameType, payload []byte) ([]byte, error) {
-	frame := make([]byte, 2+len(payload)+1)
-	frame[0] = byte(frameType)
-	copy(frame[1:], payload)
-	frame[len(frame)-1] = frames.GenerateCrc8Value(frame[:len(frame)-1])
-	return frame, nil
+// writeFrame encodes the frame into buff, which is reused between writes, and writes it to the transport
+func (c *CRSF) writeFrame(buff []byte, address frames.AddressType, frameType frames.FrameType, payload []byte) error {
+	fullFrame, err := frames.AppendFrame(buff[:0], address, frameType, payload)
+	if err != nil {
+		return fmt.Errorf("failed building frame: %w", err)
+	}
+
+	c.transportLock.Lock()
+	_, err = c.transport.Write(fullFrame)
+	c.transportLock.Unlock()
+	if err != nil {
+		c.stats.update(func(s *Stats) { s.WriteErrors++ })
+		return fmt.Errorf("failed writing to %s: %w", c.path, err)
+	}
+	c.stats.update(func(s *Stats) { s.FramesWritten++ })
+
+	if c.opts.Recorder != nil {
+		err = c.opts.Recorder.Record(CaptureWrite, fullFrame)
+		if err != nil {
+			slog.Warn("failed recording write", "path", c.path, "error", err)
+		}
+	}
+	return nil
+}
+
+// channelsPayload marshals the current channels in the configured format
+func (c *CRSF) channelsPayload() []byte {
+	c.dataLock.RLock()
+	channelsData := c.data.Channels
+	c.dataLock.RUnlock()
+
+	if c.opts.ChannelsFormat == frames.FrameTypeChannelSubSet {
+		subSet := frames.NewChannelSubSet(channelsData, c.opts.ChannelSubSet.StartChannel, c.opts.ChannelSubSet.Count, c.opts.ChannelSubSet.Resolution)
+		return subSet.MarshalChannelSubSet()
+	}
+	return channelsData.MarshalChannels()
+}
+
+// telemetryPayload marshals the current value of a telemetry frame type
+func (c *CRSF) telemetryPayload(frameType frames.FrameType) ([]byte, error) {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+
+	switch frameType {
+	case frames.FrameTypeGPS:
+		return c.data.Gps.MarshalGps(), nil
+	case frames.FrameTypeVario:
+		return c.data.Vario.MarshalVario(), nil
+	case frames.FrameTypeBatterySensor:
+		return c.data.BatterySensor.MarshalBatterySensor(), nil
+	case frames.FrameTypeBarometer:
+		return c.data.Barometer.MarshalBarometer(), nil
+	case frames.FrameTypeLinkStats:
+		return c.data.LinkStats.MarshalLinkStats(), nil
+	case frames.FrameTypeLinkRx:
+		return c.data.LinkRx.MarshalLinkRx(), nil
+	case frames.FrameTypeLinkTx:
+		return c.data.LinkTx.MarshalLinkTx(), nil
+	case frames.FrameTypeAttitude:
+		return c.data.Attitude.MarshalAttitude(), nil
+	case frames.FrameTypeFlightMode:
+		return c.data.FlightMode.MarshalFlightMode(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTelemetry, frameType.String())
+	}
 }